@@ -0,0 +1,299 @@
+// Package azurekv provides a dopplerconfig.Provider backed by Azure Key
+// Vault. It lives in its own package so only programs that import it link
+// the Azure SDK.
+package azurekv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	chassiserrors "github.com/ai8future/chassis-go/v10/errors"
+	"github.com/ai8future/dopplerconfig"
+)
+
+// DefaultVaultSuffix is the DNS suffix of vaults in the Azure public cloud.
+const DefaultVaultSuffix = "vault.azure.net"
+
+// secretsClient is the subset of *azsecrets.Client used by Provider.
+type secretsClient interface {
+	NewListSecretPropertiesPager(options *azsecrets.ListSecretPropertiesOptions) *runtime.Pager[azsecrets.ListSecretPropertiesResponse]
+	GetSecret(ctx context.Context, name, version string, options *azsecrets.GetSecretOptions) (azsecrets.GetSecretResponse, error)
+}
+
+// Provider reads configuration from Azure Key Vault.
+//
+// By default it lists every enabled secret in the vault and maps each secret
+// name to a config key by upper-casing it and replacing dashes with
+// underscores (Key Vault names cannot contain underscores), so the secret
+// "db-password" becomes DB_PASSWORD. With WithJSONSecret it instead reads a
+// single secret holding a JSON object and flattens it the same way as
+// dopplerconfig.FileProvider.
+//
+// FetchProject maps project to a vault name (https://<project>.<suffix>/,
+// where the suffix is taken from the provider's own vault URL, e.g.
+// vault.azure.cn, unless set with WithVaultSuffix) and config to either the
+// JSON secret name or, in list mode, a secret name prefix: config "tenant-a"
+// selects secrets named "tenant-a-*" with the prefix stripped.
+//
+// HTTP failures are returned as chassis-go *errors.ServiceError values, so
+// throttling (429) and auth (401/403) failures are distinguishable while still
+// triggering the loader's fallback.
+type Provider struct {
+	vaultURL      string
+	vaultSuffix   string
+	credential    azcore.TokenCredential
+	clientOptions *azsecrets.ClientOptions
+	jsonSecret    string
+	prefix        string
+
+	mu        sync.Mutex
+	clients   map[string]secretsClient
+	newClient func(vaultURL string) (secretsClient, error)
+}
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithJSONSecret makes Fetch read the named secret as a JSON object instead
+// of listing the vault.
+func WithJSONSecret(name string) Option {
+	return func(p *Provider) {
+		p.jsonSecret = name
+	}
+}
+
+// WithSecretPrefix restricts Fetch in list mode to secrets whose names
+// start with prefix. The prefix is stripped before mapping to a config key.
+func WithSecretPrefix(prefix string) Option {
+	return func(p *Provider) {
+		p.prefix = prefix
+	}
+}
+
+// WithClientOptions sets the options passed to azsecrets.NewClient.
+func WithClientOptions(options *azsecrets.ClientOptions) Option {
+	return func(p *Provider) {
+		p.clientOptions = options
+	}
+}
+
+// WithVaultSuffix sets the DNS suffix FetchProject appends to a project's
+// vault name, e.g. "vault.usgovcloudapi.net" for Azure Government. By default
+// it is taken from the provider's vault URL.
+func WithVaultSuffix(suffix string) Option {
+	return func(p *Provider) {
+		p.vaultSuffix = strings.Trim(suffix, ".")
+	}
+}
+
+// New creates a provider for the vault at vaultURL (e.g.
+// "https://myvault.vault.azure.net/"), authenticating with credential,
+// typically from azidentity.NewDefaultAzureCredential.
+func New(vaultURL string, credential azcore.TokenCredential, opts ...Option) (*Provider, error) {
+	if vaultURL == "" {
+		return nil, fmt.Errorf("azure key vault URL is required")
+	}
+	if credential == nil {
+		return nil, fmt.Errorf("azure credential is required")
+	}
+
+	p := &Provider{
+		vaultURL:    vaultURL,
+		vaultSuffix: vaultSuffix(vaultURL),
+		credential:  credential,
+		clients:     make(map[string]secretsClient),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.newClient = func(vaultURL string) (secretsClient, error) {
+		return azsecrets.NewClient(vaultURL, p.credential, p.clientOptions)
+	}
+
+	return p, nil
+}
+
+// vaultSuffix returns the host of vaultURL without its first label, such as
+// "vault.azure.cn" for "https://myvault.vault.azure.cn/", or
+// DefaultVaultSuffix if the URL has no such host.
+func vaultSuffix(vaultURL string) string {
+	u, err := url.Parse(vaultURL)
+	if err != nil {
+		return DefaultVaultSuffix
+	}
+	_, suffix, ok := strings.Cut(u.Hostname(), ".")
+	if !ok || !strings.Contains(suffix, ".") {
+		return DefaultVaultSuffix
+	}
+	return suffix
+}
+
+// Fetch reads the configured vault.
+func (p *Provider) Fetch(ctx context.Context) (map[string]string, error) {
+	if p.jsonSecret != "" {
+		return p.fetchJSON(ctx, p.vaultURL, p.jsonSecret)
+	}
+	return p.fetchList(ctx, p.vaultURL, p.prefix)
+}
+
+// FetchProject reads the vault named project. In JSON mode config names the
+// secret to read; in list mode it selects secrets prefixed "<config>-".
+// Empty arguments fall back to the provider's own vault, secret and prefix.
+func (p *Provider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	vaultURL := p.vaultURL
+	if project != "" {
+		vaultURL = "https://" + project + "." + p.vaultSuffix + "/"
+	}
+
+	if p.jsonSecret != "" {
+		name := p.jsonSecret
+		if config != "" {
+			name = config
+		}
+		return p.fetchJSON(ctx, vaultURL, name)
+	}
+
+	prefix := p.prefix
+	if config != "" {
+		prefix = config + "-"
+	}
+	return p.fetchList(ctx, vaultURL, prefix)
+}
+
+// client returns the cached client for vaultURL, creating it on first use.
+func (p *Provider) client(vaultURL string) (secretsClient, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.clients[vaultURL]; ok {
+		return c, nil
+	}
+	c, err := p.newClient(vaultURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create azure key vault client: %w", err)
+	}
+	p.clients[vaultURL] = c
+	return c, nil
+}
+
+// fetchJSON reads one secret and flattens its JSON value.
+func (p *Provider) fetchJSON(ctx context.Context, vaultURL, name string) (map[string]string, error) {
+	c, err := p.client(vaultURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.GetSecret(ctx, name, "", nil)
+	if err != nil {
+		return nil, keyVaultError(err, "get secret "+name)
+	}
+	if resp.Value == nil {
+		return nil, fmt.Errorf("azure key vault secret %s has no value", name)
+	}
+
+	return dopplerconfig.DecodeJSONValues([]byte(*resp.Value), p.Name(), "azure key vault secret "+name)
+}
+
+// fetchList reads every enabled secret whose name starts with prefix.
+func (p *Provider) fetchList(ctx context.Context, vaultURL, prefix string) (map[string]string, error) {
+	c, err := p.client(vaultURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	pager := c.NewListSecretPropertiesPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, keyVaultError(err, "list secrets")
+		}
+		for _, props := range page.Value {
+			if props == nil || props.ID == nil {
+				continue
+			}
+			if props.Attributes != nil && props.Attributes.Enabled != nil && !*props.Attributes.Enabled {
+				continue
+			}
+			name := props.ID.Name()
+			if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	raw := make(map[string]string, len(names))
+	for _, name := range names {
+		resp, err := c.GetSecret(ctx, name, "", nil)
+		if err != nil {
+			return nil, keyVaultError(err, "get secret "+name)
+		}
+		if resp.Value != nil {
+			raw[name] = *resp.Value
+		}
+	}
+
+	result := make(map[string]string, len(raw))
+	for name, value := range raw {
+		key := strings.ToUpper(strings.ReplaceAll(strings.TrimPrefix(name, prefix), "-", "_"))
+		result[key] = value
+	}
+
+	// Validate the keys the loader sees, so KeyPolicy entries are written
+	// as DB_PASSWORD rather than the secret name. Mapping only strips the
+	// prefix and changes case and dashes, so any dangerous secret name is
+	// still dangerous as a key.
+	if err := dopplerconfig.ValidateKeys(result); err != nil {
+		return nil, fmt.Errorf("azure key vault security validation failed: %w", err)
+	}
+	return result, nil
+}
+
+// keyVaultError converts Key Vault HTTP failures into chassis-go
+// ServiceErrors so callers can tell throttling and auth failures apart.
+func keyVaultError(err error, op string) error {
+	var respErr *azcore.ResponseError
+	if !errors.As(err, &respErr) {
+		return fmt.Errorf("azure key vault %s failed: %w", op, err)
+	}
+	msg := fmt.Sprintf("azure key vault %s failed: %s", op, http.StatusText(respErr.StatusCode))
+	return statusServiceError(respErr.StatusCode, msg).
+		WithDetail("azure_status", fmt.Sprintf("%d", respErr.StatusCode)).
+		WithDetail("azure_error_code", respErr.ErrorCode).
+		WithCause(err)
+}
+
+// statusServiceError maps an HTTP status code to a chassis-go ServiceError,
+// the same way DopplerProvider classifies Doppler API failures.
+func statusServiceError(statusCode int, message string) *chassiserrors.ServiceError {
+	switch {
+	case statusCode == 401 || statusCode == 403:
+		return chassiserrors.UnauthorizedError(message)
+	case statusCode == 404:
+		return chassiserrors.NotFoundError(message)
+	case statusCode == 429:
+		return chassiserrors.RateLimitError(message)
+	case statusCode >= 500:
+		return chassiserrors.DependencyError(message)
+	default:
+		return chassiserrors.InternalError(message)
+	}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "azure-keyvault:" + p.vaultURL
+}
+
+// Close is a no-op; Key Vault clients hold no resources that need releasing.
+func (p *Provider) Close() error {
+	return nil
+}
@@ -0,0 +1,252 @@
+package azurekv
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	chassiserrors "github.com/ai8future/chassis-go/v10/errors"
+	"github.com/ai8future/chassis-go/v10/secval"
+	"github.com/ai8future/dopplerconfig"
+)
+
+// fakeKeyVault is an in-memory secretsClient.
+type fakeKeyVault struct {
+	url      string
+	secrets  map[string]string
+	disabled map[string]bool
+	getErr   error
+}
+
+func (f *fakeKeyVault) NewListSecretPropertiesPager(*azsecrets.ListSecretPropertiesOptions) *runtime.Pager[azsecrets.ListSecretPropertiesResponse] {
+	var props []*azsecrets.SecretProperties
+	for name := range f.secrets {
+		id := azsecrets.ID(f.url + "secrets/" + name)
+		enabled := !f.disabled[name]
+		props = append(props, &azsecrets.SecretProperties{
+			ID:         &id,
+			Attributes: &azsecrets.SecretAttributes{Enabled: &enabled},
+		})
+	}
+	return runtime.NewPager(runtime.PagingHandler[azsecrets.ListSecretPropertiesResponse]{
+		More: func(azsecrets.ListSecretPropertiesResponse) bool { return false },
+		Fetcher: func(context.Context, *azsecrets.ListSecretPropertiesResponse) (azsecrets.ListSecretPropertiesResponse, error) {
+			return azsecrets.ListSecretPropertiesResponse{
+				SecretPropertiesListResult: azsecrets.SecretPropertiesListResult{Value: props},
+			}, nil
+		},
+	})
+}
+
+func (f *fakeKeyVault) GetSecret(_ context.Context, name, _ string, _ *azsecrets.GetSecretOptions) (azsecrets.GetSecretResponse, error) {
+	if f.getErr != nil {
+		return azsecrets.GetSecretResponse{}, f.getErr
+	}
+	value, ok := f.secrets[name]
+	if !ok {
+		return azsecrets.GetSecretResponse{}, &azcore.ResponseError{StatusCode: http.StatusNotFound, ErrorCode: "SecretNotFound"}
+	}
+	return azsecrets.GetSecretResponse{Secret: azsecrets.Secret{Value: &value}}, nil
+}
+
+// fakeCredential satisfies azcore.TokenCredential; it is never called.
+type fakeCredential struct{ azcore.TokenCredential }
+
+func newTestAzureProvider(t *testing.T, vaults map[string]*fakeKeyVault, opts ...Option) *Provider {
+	t.Helper()
+	p, err := New("https://main.vault.azure.net/", fakeCredential{}, opts...)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	p.newClient = func(vaultURL string) (secretsClient, error) {
+		v, ok := vaults[vaultURL]
+		if !ok {
+			t.Fatalf("unexpected vault %s", vaultURL)
+		}
+		v.url = vaultURL
+		return v, nil
+	}
+	return p
+}
+
+func TestProvider_List(t *testing.T) {
+	p := newTestAzureProvider(t, map[string]*fakeKeyVault{
+		"https://main.vault.azure.net/": {
+			secrets:  map[string]string{"db-password": "s3cret", "port": "8080", "old-key": "x"},
+			disabled: map[string]bool{"old-key": true},
+		},
+	})
+
+	values, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if values["DB_PASSWORD"] != "s3cret" || values["PORT"] != "8080" {
+		t.Errorf("values = %v, want DB_PASSWORD and PORT", values)
+	}
+	if _, ok := values["OLD_KEY"]; ok {
+		t.Error("disabled secret should be skipped")
+	}
+}
+
+func TestProvider_FetchProjectPrefix(t *testing.T) {
+	p := newTestAzureProvider(t, map[string]*fakeKeyVault{
+		"https://tenants.vault.azure.net/": {
+			secrets: map[string]string{"acme-api-key": "a", "globex-api-key": "g"},
+		},
+	})
+
+	values, err := p.FetchProject(context.Background(), "tenants", "acme")
+	if err != nil {
+		t.Fatalf("FetchProject failed: %v", err)
+	}
+	if len(values) != 1 || values["API_KEY"] != "a" {
+		t.Errorf("values = %v, want only API_KEY=a", values)
+	}
+}
+
+func TestProvider_KeyPolicyChecksMappedKeys(t *testing.T) {
+	t.Cleanup(func() { dopplerconfig.SetKeyPolicy(dopplerconfig.KeyPolicy{}) })
+	p := newTestAzureProvider(t, map[string]*fakeKeyVault{
+		"https://main.vault.azure.net/": {
+			secrets: map[string]string{"db-password": "s3cret", "port": "8080"},
+		},
+	})
+	ctx := context.Background()
+
+	// The pattern is matched against DB_PASSWORD, not db-password.
+	dopplerconfig.SetKeyPolicy(dopplerconfig.KeyPolicy{Pattern: regexp.MustCompile(`^[A-Z0-9_]+$`)})
+	values, err := p.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch with uppercase-only pattern failed: %v", err)
+	}
+	if values["DB_PASSWORD"] != "s3cret" {
+		t.Errorf("values = %v, want DB_PASSWORD", values)
+	}
+
+	dopplerconfig.SetKeyPolicy(dopplerconfig.KeyPolicy{Deny: []string{"DB_PASSWORD"}})
+	if _, err := p.Fetch(ctx); !errors.Is(err, secval.ErrDangerousKey) {
+		t.Errorf("Fetch error = %v, want DB_PASSWORD denied", err)
+	}
+
+	// Dangerous keys are still rejected after mapping.
+	dopplerconfig.SetKeyPolicy(dopplerconfig.KeyPolicy{})
+	p = newTestAzureProvider(t, map[string]*fakeKeyVault{
+		"https://main.vault.azure.net/": {
+			secrets: map[string]string{"app-constructor": "x"},
+		},
+	}, WithSecretPrefix("app-"))
+	if _, err := p.Fetch(ctx); !errors.Is(err, secval.ErrDangerousKey) {
+		t.Errorf("Fetch error = %v, want CONSTRUCTOR rejected", err)
+	}
+}
+
+func TestProvider_FetchProjectVaultSuffix(t *testing.T) {
+	newProvider := func(vaultURL string, opts ...Option) *Provider {
+		p, err := New(vaultURL, fakeCredential{}, opts...)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		return p
+	}
+	tests := []struct {
+		name string
+		p    *Provider
+		want string
+	}{
+		{"public cloud", newProvider("https://main.vault.azure.net/"), "https://tenants.vault.azure.net/"},
+		{"china cloud from vault URL", newProvider("https://main.vault.azure.cn/"), "https://tenants.vault.azure.cn/"},
+		{"explicit suffix", newProvider("https://main.vault.azure.net/", WithVaultSuffix("vault.usgovcloudapi.net")), "https://tenants.vault.usgovcloudapi.net/"},
+		{"unparseable vault URL", newProvider("main"), "https://tenants.vault.azure.net/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			tt.p.newClient = func(vaultURL string) (secretsClient, error) {
+				got = vaultURL
+				return &fakeKeyVault{url: vaultURL, secrets: map[string]string{"acme-port": "1"}}, nil
+			}
+			if _, err := tt.p.FetchProject(context.Background(), "tenants", "acme"); err != nil {
+				t.Fatalf("FetchProject failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("vault URL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvider_JSONSecret(t *testing.T) {
+	p := newTestAzureProvider(t, map[string]*fakeKeyVault{
+		"https://main.vault.azure.net/": {
+			secrets: map[string]string{
+				"app-config": `{"server":{"port":8080},"debug":true}`,
+				"evil":       `{"__proto__":{"x":1}}`,
+			},
+		},
+	}, WithJSONSecret("app-config"))
+
+	values, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if values["server_port"] != "8080" || values["debug"] != "true" {
+		t.Errorf("values = %v, want flattened JSON", values)
+	}
+
+	_, err = p.FetchProject(context.Background(), "", "evil")
+	var perr *dopplerconfig.ProviderError
+	if !errors.As(err, &perr) || perr.Kind != dopplerconfig.ProviderErrorInvalid {
+		t.Errorf("dangerous key in JSON secret error = %v, want ProviderErrorInvalid", err)
+	}
+}
+
+func TestProvider_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		status   int
+		wantHTTP int
+	}{
+		{http.StatusTooManyRequests, 429},
+		{http.StatusForbidden, 401},
+		{http.StatusServiceUnavailable, 503},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			p := newTestAzureProvider(t, map[string]*fakeKeyVault{
+				"https://main.vault.azure.net/": {
+					secrets: map[string]string{"key": "v"},
+					getErr:  &azcore.ResponseError{StatusCode: tt.status},
+				},
+			})
+
+			_, err := p.Fetch(context.Background())
+			var se *chassiserrors.ServiceError
+			if !errors.As(err, &se) {
+				t.Fatalf("error = %v, want *ServiceError", err)
+			}
+			if se.HTTPCode != tt.wantHTTP {
+				t.Errorf("HTTPCode = %d, want %d", se.HTTPCode, tt.wantHTTP)
+			}
+			var respErr *azcore.ResponseError
+			if !errors.As(err, &respErr) {
+				t.Error("ServiceError should wrap the azcore.ResponseError")
+			}
+		})
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	if _, err := New("", fakeCredential{}); err == nil {
+		t.Error("expected error for empty vault URL")
+	}
+	if _, err := New("https://v.vault.azure.net/", nil); err == nil {
+		t.Error("expected error for nil credential")
+	}
+}
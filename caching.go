@@ -0,0 +1,163 @@
+package dopplerconfig
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+// CachingProvider wraps another provider and serves each fetch result from
+// memory until its TTL expires, then fetches again. Useful in front of
+// providers without their own caching, such as FileProvider and EnvProvider.
+//
+// Fetch and each FetchProject project/config pair are cached separately.
+// Refreshes are single-flight: concurrent fetches of an expired entry wait
+// for one call to the wrapped provider instead of each making their own. A
+// waiter whose ctx ends first returns ctx.Err() without waiting for the
+// refresh, and if the caller running the refresh gives up, waiters with a
+// live ctx start a new one. Errors are not cached.
+type CachingProvider struct {
+	provider Provider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]*cacheEntry
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// cacheKey identifies a cached fetch; Fetch uses the zero key.
+type cacheKey struct {
+	project, config string
+	isProject       bool
+}
+
+// cacheEntry holds one cached result and the refresh in progress, if any.
+// Its fields are guarded by CachingProvider.mu.
+type cacheEntry struct {
+	values  map[string]string
+	expires time.Time
+	refresh *cacheRefresh
+}
+
+// cacheRefresh is a fetch from the wrapped provider in progress; done is
+// closed once values and err are set.
+type cacheRefresh struct {
+	done   chan struct{}
+	values map[string]string
+	err    error
+
+	// leaderDone records that the ctx of the caller running the fetch
+	// ended before it finished, so err is that caller's, not the others'.
+	leaderDone bool
+
+	// waiters counts the callers that joined the refresh after it started.
+	waiters int
+}
+
+// NewCachingProvider wraps provider so each result is reused for ttl.
+func NewCachingProvider(provider Provider, ttl time.Duration) *CachingProvider {
+	return &CachingProvider{
+		provider: provider,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[cacheKey]*cacheEntry),
+	}
+}
+
+// Fetch returns the cached values, refreshing them from the wrapped
+// provider once the TTL has expired.
+func (p *CachingProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	return p.get(ctx, cacheKey{}, func() (map[string]string, error) {
+		return p.provider.Fetch(ctx)
+	})
+}
+
+// FetchProject returns the cached values for project and config,
+// refreshing them from the wrapped provider once the TTL has expired.
+func (p *CachingProvider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	key := cacheKey{project: project, config: config, isProject: true}
+	return p.get(ctx, key, func() (map[string]string, error) {
+		return p.provider.FetchProject(ctx, project, config)
+	})
+}
+
+func (p *CachingProvider) get(ctx context.Context, key cacheKey, fetch func() (map[string]string, error)) (map[string]string, error) {
+	for {
+		p.mu.Lock()
+		entry, ok := p.entries[key]
+		if !ok {
+			entry = &cacheEntry{}
+			p.entries[key] = entry
+		}
+		if entry.values != nil && p.now().Before(entry.expires) {
+			values := maps.Clone(entry.values)
+			p.mu.Unlock()
+			return values, nil
+		}
+
+		if r := entry.refresh; r != nil {
+			r.waiters++
+			p.mu.Unlock()
+			select {
+			case <-r.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if r.leaderDone && r.err != nil && ctx.Err() == nil {
+				continue
+			}
+			if r.err != nil {
+				return nil, r.err
+			}
+			return maps.Clone(r.values), nil
+		}
+
+		r := &cacheRefresh{done: make(chan struct{})}
+		entry.refresh = r
+		p.mu.Unlock()
+
+		values, err := fetch()
+
+		p.mu.Lock()
+		entry.refresh = nil
+		if err == nil {
+			entry.values = values
+			entry.expires = p.now().Add(p.ttl)
+		}
+		r.values, r.err = values, err
+		r.leaderDone = ctx.Err() != nil
+		p.mu.Unlock()
+		close(r.done)
+
+		if err != nil {
+			return nil, err
+		}
+		return maps.Clone(values), nil
+	}
+}
+
+// InvalidateCache drops every cached result, so the next fetches go to the
+// wrapped provider, e.g. after a webhook reports a change.
+func (p *CachingProvider) InvalidateCache() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entries = make(map[cacheKey]*cacheEntry)
+}
+
+// Name returns the wrapped provider's name.
+func (p *CachingProvider) Name() string {
+	return "cached:" + p.provider.Name()
+}
+
+// Close closes the wrapped provider once; later calls return the same
+// result without closing it again.
+func (p *CachingProvider) Close() error {
+	p.closeOnce.Do(func() {
+		p.closeErr = p.provider.Close()
+	})
+	return p.closeErr
+}
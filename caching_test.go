@@ -0,0 +1,258 @@
+package dopplerconfig
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCachingProvider_TTL(t *testing.T) {
+	mock := NewMockProvider(map[string]string{"KEY": "v1"})
+	mock.SetProjectValues("proj", "prd", map[string]string{"KEY": "p1"})
+	recorder := NewRecordingProvider(mock)
+
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := NewCachingProvider(recorder, time.Minute)
+	p.now = func() time.Time { return clock }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		values, err := p.Fetch(ctx)
+		if err != nil {
+			t.Fatalf("Fetch failed: %v", err)
+		}
+		if values["KEY"] != "v1" {
+			t.Errorf("KEY = %q, want %q", values["KEY"], "v1")
+		}
+		if _, err := p.FetchProject(ctx, "proj", "prd"); err != nil {
+			t.Fatalf("FetchProject failed: %v", err)
+		}
+	}
+	if got := recorder.CallCount(); got != 2 {
+		t.Errorf("underlying calls = %d, want 2 (one Fetch, one FetchProject)", got)
+	}
+
+	// Still within the TTL: served from cache even though the source changed.
+	mock.SetValue("KEY", "v2")
+	clock = clock.Add(59 * time.Second)
+	if values, _ := p.Fetch(ctx); values["KEY"] != "v1" {
+		t.Errorf("KEY = %q within TTL, want cached %q", values["KEY"], "v1")
+	}
+
+	// Expired: refetched once, then cached again.
+	clock = clock.Add(time.Second)
+	recorder.Reset()
+	for i := 0; i < 3; i++ {
+		if values, _ := p.Fetch(ctx); values["KEY"] != "v2" {
+			t.Errorf("KEY = %q after TTL, want %q", values["KEY"], "v2")
+		}
+	}
+	if got := recorder.CallCount(); got != 1 {
+		t.Errorf("underlying calls after expiry = %d, want 1", got)
+	}
+
+	p.InvalidateCache()
+	if _, err := p.Fetch(ctx); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if got := recorder.CallCount(); got != 2 {
+		t.Errorf("underlying calls after InvalidateCache = %d, want 2", got)
+	}
+}
+
+func TestCachingProvider_SingleFlight(t *testing.T) {
+	recorder := NewRecordingProvider(NewMockProvider(map[string]string{"KEY": "v"}))
+	p := NewCachingProvider(NewSlowProvider(recorder, 20*time.Millisecond), time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if values, err := p.Fetch(context.Background()); err != nil || values["KEY"] != "v" {
+				t.Errorf("Fetch = %v, %v", values, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := recorder.CallCount(); got != 1 {
+		t.Errorf("underlying calls = %d, want 1", got)
+	}
+}
+
+func TestCachingProvider_WaiterContextExpires(t *testing.T) {
+	provider := &blockingProvider{
+		MockProvider: NewMockProvider(map[string]string{"KEY": "v"}),
+		entered:      make(chan struct{}, 1),
+		release:      make(chan struct{}),
+	}
+	p := NewCachingProvider(provider, time.Minute)
+
+	leader := make(chan error, 1)
+	go func() {
+		_, err := p.Fetch(context.Background())
+		leader <- err
+	}()
+	<-provider.entered
+
+	// The refresh is stuck on the provider; a waiter with a deadline
+	// gives up when its deadline passes.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := p.Fetch(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiter Fetch error = %v, want context.DeadlineExceeded", err)
+	}
+
+	close(provider.release)
+	if err := <-leader; err != nil {
+		t.Fatalf("leader Fetch failed: %v", err)
+	}
+	if n := provider.fetches.Load(); n != 1 {
+		t.Errorf("provider fetched %d times, want 1", n)
+	}
+}
+
+// stallOnceProvider holds its first Fetch until ctx is done and serves
+// later ones normally.
+type stallOnceProvider struct {
+	*MockProvider
+	fetches atomic.Int32
+}
+
+func (p *stallOnceProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	if p.fetches.Add(1) == 1 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return p.MockProvider.Fetch(ctx)
+}
+
+func TestCachingProvider_LeaderCancelled(t *testing.T) {
+	provider := &stallOnceProvider{MockProvider: NewMockProvider(map[string]string{"KEY": "v"})}
+	p := NewCachingProvider(provider, time.Minute)
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() {
+		_, err := p.Fetch(leaderCtx)
+		leader <- err
+	}()
+	waitForCacheWaiters(t, p, 0)
+
+	waiter := make(chan error, 1)
+	go func() {
+		values, err := p.Fetch(context.Background())
+		if err == nil && values["KEY"] != "v" {
+			err = errors.New("waiter got KEY=" + values["KEY"])
+		}
+		waiter <- err
+	}()
+	waitForCacheWaiters(t, p, 1)
+
+	// The leader gives up; the waiter retries instead of returning the
+	// leader's cancellation.
+	cancelLeader()
+	if err := <-leader; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader error = %v, want context.Canceled", err)
+	}
+	if err := <-waiter; err != nil {
+		t.Errorf("waiter error = %v, want nil", err)
+	}
+	if n := provider.fetches.Load(); n != 2 {
+		t.Errorf("provider fetched %d times, want 2", n)
+	}
+}
+
+// waitForCacheWaiters waits until a refresh of the Fetch entry is in
+// progress with at least n callers waiting on it.
+func waitForCacheWaiters(t *testing.T, p *CachingProvider, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		p.mu.Lock()
+		got := -1
+		if entry := p.entries[cacheKey{}]; entry != nil && entry.refresh != nil {
+			got = entry.refresh.waiters
+		}
+		p.mu.Unlock()
+		if got >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("refresh has %d waiters, want %d", got, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCachingProvider_ErrorsNotCached(t *testing.T) {
+	mock := NewMockProvider(map[string]string{"KEY": "v"})
+	mock.SetError(errors.New("unavailable"))
+	p := NewCachingProvider(mock, time.Minute)
+
+	if _, err := p.Fetch(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+
+	mock.SetError(nil)
+	values, err := p.Fetch(context.Background())
+	if err != nil || values["KEY"] != "v" {
+		t.Errorf("Fetch = %v, %v; want recovered values", values, err)
+	}
+	if p.Name() != "cached:mock" {
+		t.Errorf("Name = %q, want %q", p.Name(), "cached:mock")
+	}
+}
+
+// channelProvider owns a channel that Close closes, so closing it twice
+// would panic the way a double-freed resource would.
+type channelProvider struct {
+	*MockProvider
+	done   chan struct{}
+	closes atomic.Int32
+}
+
+func newChannelProvider() *channelProvider {
+	return &channelProvider{MockProvider: NewMockProvider(nil), done: make(chan struct{})}
+}
+
+func (p *channelProvider) Close() error {
+	p.closes.Add(1)
+	close(p.done)
+	return nil
+}
+
+func TestProviders_CloseIsIdempotent(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(p Provider) (close func() error)
+	}{
+		{"CachingProvider", func(p Provider) func() error {
+			return NewCachingProvider(p, time.Minute).Close
+		}},
+		{"RecordingProvider", func(p Provider) func() error {
+			return NewRecordingProvider(p).Close
+		}},
+		{"MultiTenantLoader", func(p Provider) func() error {
+			return NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](p, nil).Close
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := newChannelProvider()
+			closeFn := tt.wrap(inner)
+			for range 2 {
+				if err := closeFn(); err != nil {
+					t.Fatalf("Close failed: %v", err)
+				}
+			}
+			if got := inner.closes.Load(); got != 1 {
+				t.Errorf("underlying Close called %d times, want 1", got)
+			}
+		})
+	}
+}
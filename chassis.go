@@ -2,6 +2,10 @@ package dopplerconfig
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	chassis "github.com/ai8future/chassis-go/v10"
@@ -18,6 +22,7 @@ type bootstrapEnv struct {
 	FallbackPath  string `env:"DOPPLER_FALLBACK_PATH" required:"false"`
 	WatchEnabled  string `env:"DOPPLER_WATCH_ENABLED" required:"false"`
 	FailurePolicy string `env:"DOPPLER_FAILURE_POLICY" default:"fallback" required:"false"`
+	Offline       string `env:"DOPPLER_OFFLINE" required:"false"`
 }
 
 // LoadBootstrapWithChassis loads BootstrapConfig using chassis-go's
@@ -38,6 +43,7 @@ func LoadBootstrapWithChassis() BootstrapConfig {
 		WatchEnabled:  raw.WatchEnabled == "true",
 		WatchInterval: 30 * time.Second,
 		FailurePolicy: FailurePolicyFallback,
+		Offline:       raw.Offline == "true",
 	}
 
 	switch raw.FailurePolicy {
@@ -105,3 +111,129 @@ func HealthCheck(provider *DopplerProvider) func(ctx context.Context) error {
 		return err
 	}
 }
+
+// DegradedError is returned by HealthCheckWithFallback when configuration is
+// being served from a fallback source instead of the primary provider.
+// Health handlers can map it to a warning status rather than a failure.
+type DegradedError struct {
+	// Source is the provider the current config was loaded from.
+	Source string
+
+	// LoadedAt is when the current config was loaded.
+	LoadedAt time.Time
+}
+
+func (e *DegradedError) Error() string {
+	return fmt.Sprintf("config degraded: serving from fallback %s (loaded %s)",
+		e.Source, e.LoadedAt.Format(time.RFC3339))
+}
+
+// IsDegraded checks if an error is a DegradedError.
+// Uses errors.As for proper error chain unwrapping.
+func IsDegraded(err error) (*DegradedError, bool) {
+	var de *DegradedError
+	if errors.As(err, &de) {
+		return de, true
+	}
+	return nil, false
+}
+
+// HealthCheckWithFallback returns a health check function for a Loader that
+// distinguishes three states using ConfigMetadata.Role from the most recent
+// Load or Reload:
+//   - healthy (nil): config was loaded from the primary provider
+//   - degraded (*DegradedError): config was loaded from the fallback provider
+//   - unhealthy (other error): nothing loaded, or only struct defaults are in use
+//
+// For Loader implementations that do not record a Role, the config counts
+// as degraded when Providers reports no primary. The check does not fetch;
+// pair it with a Watcher to keep the state fresh.
+func HealthCheckWithFallback[T any](l Loader[T]) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if l.Current() == nil {
+			return fmt.Errorf("config not loaded")
+		}
+
+		meta := l.Metadata()
+		if meta.Source == "" || meta.Source == SourceDefaults {
+			return fmt.Errorf("no configuration source available: serving struct defaults")
+		}
+
+		switch meta.Role {
+		case RolePrimary:
+			return nil
+		case RoleFallback:
+			return &DegradedError{Source: meta.Source, LoadedAt: meta.LoadedAt}
+		}
+		if primary, _ := l.Providers(); primary == nil {
+			return &DegradedError{Source: meta.Source, LoadedAt: meta.LoadedAt}
+		}
+		return nil
+	}
+}
+
+// TenantHealthError is returned by MultiTenantHealthCheck when the
+// multi-tenant configuration is unhealthy. It carries per-tenant detail so a
+// health handler can render which tenants failed and why.
+type TenantHealthError struct {
+	// EnvLoaded reports whether the environment-level config is loaded.
+	EnvLoaded bool
+
+	// Loaded is the number of tenant configs currently loaded.
+	Loaded int
+
+	// Expected is the number of tenants the loader has attempted to load.
+	Expected int
+
+	// Failed maps each failing tenant code to its most recent load error.
+	Failed map[string]error
+}
+
+func (e *TenantHealthError) Error() string {
+	if !e.EnvLoaded {
+		return "multi-tenant config unhealthy: env config not loaded"
+	}
+	codes := make([]string, 0, len(e.Failed))
+	for code := range e.Failed {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return fmt.Sprintf("multi-tenant config unhealthy: %d/%d tenants failed (%s)",
+		len(e.Failed), e.Expected, strings.Join(codes, ", "))
+}
+
+// MultiTenantHealthCheck returns a health check function for a
+// MultiTenantLoader. It fails with a *TenantHealthError when the env config
+// is not loaded, or when the fraction of tenants whose most recent load
+// failed exceeds maxFailedFraction (0.0-1.0; 0 means any failure is
+// unhealthy). Failures come from l.ProjectErrors, so wrappers and other
+// MultiTenantLoader implementations report them too. Expected tenants are
+// those loaded plus those that failed.
+func MultiTenantHealthCheck[E any, P any](l MultiTenantLoader[E, P], maxFailedFraction float64) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		loaded := l.ProjectCodes()
+		status := &TenantHealthError{
+			EnvLoaded: l.Env() != nil,
+			Loaded:    len(loaded),
+			Expected:  len(loaded),
+			Failed:    l.ProjectErrors(),
+		}
+		if status.Failed == nil {
+			status.Failed = map[string]error{}
+		}
+		for code := range status.Failed {
+			// A tenant whose reload failed may still serve its previous config.
+			if _, ok := l.Project(code); !ok {
+				status.Expected++
+			}
+		}
+
+		if !status.EnvLoaded {
+			return status
+		}
+		if status.Expected > 0 && float64(len(status.Failed))/float64(status.Expected) > maxFailedFraction {
+			return status
+		}
+		return nil
+	}
+}
@@ -2,11 +2,17 @@ package dopplerconfig
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	chassis "github.com/ai8future/chassis-go/v10"
 	"github.com/ai8future/chassis-go/v10/call"
@@ -181,6 +187,83 @@ func TestDopplerProvider_WithHTTPClient_NilBreaker(t *testing.T) {
 	}
 }
 
+func TestDopplerProvider_OnCircuitStateChange(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"secrets":{"A":{"raw":"1"}}}`))
+	}))
+	defer srv.Close()
+
+	provider, err := NewDopplerProvider("test-token", "proj", "dev", WithAPIURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+	defer provider.Close()
+	breaker := call.GetBreaker("doppler-test-"+t.Name(), 2, 20*time.Millisecond)
+	provider.breaker = breaker
+	provider.client = call.New(call.WithBreaker(breaker))
+
+	type transition struct{ old, new call.State }
+	var mu sync.Mutex
+	var seen []transition
+	provider.OnCircuitStateChange(func(old, new call.State) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, transition{old, new})
+	})
+
+	ctx := context.Background()
+	for range 3 {
+		if _, err := provider.Fetch(ctx); err == nil {
+			t.Fatal("Fetch should fail while Doppler is down")
+		}
+	}
+	mu.Lock()
+	if want := []transition{{call.StateClosed, call.StateOpen}}; !slices.Equal(seen, want) {
+		t.Errorf("transitions = %v, want %v", seen, want)
+	}
+	mu.Unlock()
+
+	failing.Store(false)
+	time.Sleep(30 * time.Millisecond)
+	if _, err := provider.Fetch(ctx); err != nil {
+		t.Fatalf("Fetch after recovery failed: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	want := []transition{{call.StateClosed, call.StateOpen}, {call.StateOpen, call.StateClosed}}
+	if !slices.Equal(seen, want) {
+		t.Errorf("transitions = %v, want %v", seen, want)
+	}
+}
+
+func TestDopplerProvider_OnCircuitStateChange_NoBreaker(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	provider, err := NewDopplerProvider("test-token", "proj", "dev",
+		WithAPIURL(srv.URL),
+		WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+	defer provider.Close()
+	provider.OnCircuitStateChange(func(old, new call.State) {
+		t.Errorf("unexpected transition %d -> %d without a breaker", old, new)
+	})
+	for range 6 {
+		provider.Fetch(context.Background())
+	}
+}
+
 func TestDopplerProvider_WithLogger(t *testing.T) {
 	logger := testkit.NewLogger(t)
 
@@ -326,6 +409,55 @@ func TestDopplerError_ServiceError(t *testing.T) {
 	}
 }
 
+func TestProviderError_ServiceError(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing.json")
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantKind ProviderErrorKind
+		wantHTTP int
+	}{
+		{"missing file", missing, ProviderErrorUnavailable, 503},
+		{"parse error", bad, ProviderErrorInvalid, 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Errors stay classifiable after the loader wraps them.
+			loader := NewLoaderWithProvider[TestConfig](nil, NewFileProvider(tt.path))
+			_, err := loader.Load(context.Background())
+
+			pe, ok := IsProviderError(err)
+			if !ok {
+				t.Fatalf("Load error = %v, want a ProviderError", err)
+			}
+			if pe.Kind != tt.wantKind {
+				t.Errorf("Kind = %q, want %q", pe.Kind, tt.wantKind)
+			}
+			se := pe.ServiceError()
+			if se.HTTPCode != tt.wantHTTP {
+				t.Errorf("ServiceError().HTTPCode = %d, want %d", se.HTTPCode, tt.wantHTTP)
+			}
+			if se.Details["provider"] != "file:"+tt.path {
+				t.Errorf("ServiceError().Details[provider] = %q, want %q", se.Details["provider"], "file:"+tt.path)
+			}
+			if se.Unwrap() != pe {
+				t.Error("ServiceError().Unwrap() should return original ProviderError")
+			}
+		})
+	}
+
+	if _, err := NewFileProvider(missing).Fetch(context.Background()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file error = %v, want to wrap os.ErrNotExist", err)
+	}
+}
+
 func TestSecvalRejectsDangerousKeys_DopplerResponse(t *testing.T) {
 	// Mock server returns JSON with a dangerous key
 	srv := newTestDopplerServer(t, `{"secrets":{"__proto__":{"raw":"evil"}}}`, 200)
@@ -359,6 +491,225 @@ func TestSecvalRejectsDangerousKeys_FallbackFile(t *testing.T) {
 	}
 }
 
+func TestHealthCheckWithFallback_Healthy(t *testing.T) {
+	primary := NewMockProvider(map[string]string{"DATABASE_URL": "postgres://primary/db"})
+	fallback := NewMockProvider(map[string]string{"DATABASE_URL": "postgres://fallback/db"})
+	l := NewLoaderWithProvider[TestConfig](primary, fallback)
+
+	if _, err := l.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if err := HealthCheckWithFallback(l)(context.Background()); err != nil {
+		t.Errorf("HealthCheckWithFallback returned error for primary source: %v", err)
+	}
+}
+
+func TestHealthCheckWithFallback_Degraded(t *testing.T) {
+	primary := NewMockProviderWithError(fmt.Errorf("doppler down"))
+	fallback := NewFileProvider(writeTestFallback(t, `{"DATABASE_URL": "postgres://fallback/db"}`))
+	l := NewLoaderWithProvider[TestConfig](primary, fallback)
+
+	if _, err := l.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	err := HealthCheckWithFallback(l)(context.Background())
+	de, ok := IsDegraded(err)
+	if !ok {
+		t.Fatalf("HealthCheckWithFallback error = %v, want *DegradedError", err)
+	}
+	if de.Source != fallback.Name() {
+		t.Errorf("DegradedError.Source = %q, want %q", de.Source, fallback.Name())
+	}
+}
+
+func TestHealthCheckWithFallback_SameNameFallback(t *testing.T) {
+	// Both mocks are named "mock", so only the recorded role can tell
+	// which one served the config.
+	primary := NewMockProviderWithError(fmt.Errorf("doppler down"))
+	fallback := NewMockProvider(map[string]string{"DATABASE_URL": "postgres://fallback/db"})
+	l := NewLoaderWithProvider[TestConfig](primary, fallback)
+
+	if _, err := l.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if role := l.Metadata().Role; role != RoleFallback {
+		t.Errorf("Metadata().Role = %q, want %q", role, RoleFallback)
+	}
+	if _, ok := IsDegraded(HealthCheckWithFallback(l)(context.Background())); !ok {
+		t.Error("fallback with the primary's name should be reported degraded")
+	}
+
+	primary.SetError(nil)
+	primary.SetValue("DATABASE_URL", "postgres://primary/db")
+	if _, err := l.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if err := HealthCheckWithFallback(l)(context.Background()); err != nil {
+		t.Errorf("HealthCheckWithFallback after primary recovered = %v, want nil", err)
+	}
+}
+
+func TestHealthCheckWithFallback_ProjectLoader(t *testing.T) {
+	primary := NewMockProviderWithError(fmt.Errorf("doppler down"))
+	fallback := NewMockProvider(nil)
+	fallback.SetProjectValues("", "acme", map[string]string{"PROJECT_NAME": "Acme"})
+	mt := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](primary, fallback)
+	pl := &projectLoader[MTEnvConfig, MTProjectConfig]{loader: mt, code: "acme"}
+
+	if _, err := pl.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, ok := IsDegraded(HealthCheckWithFallback[MTProjectConfig](pl)(context.Background())); !ok {
+		t.Error("project served by the fallback should be reported degraded")
+	}
+}
+
+func TestHealthCheckWithFallback_Unhealthy(t *testing.T) {
+	primary := NewMockProviderWithError(fmt.Errorf("doppler down"))
+	fallback := NewMockProviderWithError(fmt.Errorf("file missing"))
+	l := NewLoaderWithProvider[TestConfig](primary, fallback)
+
+	check := HealthCheckWithFallback(l)
+
+	// Nothing loaded yet.
+	if _, err := l.Load(context.Background()); err == nil {
+		t.Fatal("Load should fail when all providers fail")
+	}
+	err := check(context.Background())
+	if err == nil {
+		t.Fatal("HealthCheckWithFallback should fail when nothing is loaded")
+	}
+	if _, ok := IsDegraded(err); ok {
+		t.Error("unloaded config should be unhealthy, not degraded")
+	}
+}
+
+func TestHealthCheckWithFallback_DefaultsOnly(t *testing.T) {
+	primary := NewMockProviderWithError(fmt.Errorf("doppler down"))
+	impl := NewLoaderWithProvider[EnvTagConfig](primary, nil).(*loader[EnvTagConfig])
+	impl.bootstrap.FailurePolicy = FailurePolicyWarn
+
+	if _, err := impl.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	err := HealthCheckWithFallback[EnvTagConfig](impl)(context.Background())
+	if err == nil {
+		t.Fatal("HealthCheckWithFallback should fail when serving struct defaults only")
+	}
+	if _, ok := IsDegraded(err); ok {
+		t.Error("defaults-only config should be unhealthy, not degraded")
+	}
+}
+
+// partialFailureProvider fails FetchProject for the configured codes and
+// delegates everything else to the wrapped MockProvider.
+type partialFailureProvider struct {
+	*MockProvider
+	fail map[string]bool
+}
+
+func (p *partialFailureProvider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	if p.fail[config] {
+		return nil, fmt.Errorf("tenant %s unavailable", config)
+	}
+	return p.MockProvider.FetchProject(ctx, project, config)
+}
+
+func TestMultiTenantHealthCheck(t *testing.T) {
+	mock := NewMockProvider(map[string]string{"REGION": "us-east-1"})
+	for _, code := range []string{"a", "b", "c", "d"} {
+		mock.SetProjectValues("", code, map[string]string{"PROJECT_NAME": code})
+	}
+	provider := &partialFailureProvider{MockProvider: mock, fail: map[string]bool{"c": true}}
+	l := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](provider, nil)
+	ctx := context.Background()
+
+	// Env not loaded yet.
+	err := MultiTenantHealthCheck(l, 0.5)(ctx)
+	var the *TenantHealthError
+	if !errors.As(err, &the) || the.EnvLoaded {
+		t.Fatalf("health error = %v, want TenantHealthError with EnvLoaded=false", err)
+	}
+
+	if _, err := l.LoadEnv(ctx); err != nil {
+		t.Fatalf("LoadEnv failed: %v", err)
+	}
+	for _, code := range []string{"a", "b", "c", "d"} {
+		l.LoadProject(ctx, code)
+	}
+
+	// 1 of 4 failed: within a 50% budget.
+	if err := MultiTenantHealthCheck(l, 0.5)(ctx); err != nil {
+		t.Errorf("health check with 1/4 failed and 0.5 budget = %v, want nil", err)
+	}
+
+	// Same state with a zero budget reports per-tenant detail.
+	err = MultiTenantHealthCheck(l, 0)(ctx)
+	if !errors.As(err, &the) {
+		t.Fatalf("health error = %v, want *TenantHealthError", err)
+	}
+	if the.Loaded != 3 || the.Expected != 4 {
+		t.Errorf("Loaded/Expected = %d/%d, want 3/4", the.Loaded, the.Expected)
+	}
+	if _, ok := the.Failed["c"]; !ok || len(the.Failed) != 1 {
+		t.Errorf("Failed = %v, want only tenant c", the.Failed)
+	}
+
+	// Recovering the tenant clears its failure.
+	provider.fail = nil
+	if _, err := l.LoadProject(ctx, "c"); err != nil {
+		t.Fatalf("LoadProject(c) failed: %v", err)
+	}
+	if err := MultiTenantHealthCheck(l, 0)(ctx); err != nil {
+		t.Errorf("health check after recovery = %v, want nil", err)
+	}
+}
+
+// wrappedMultiTenantLoader hides the concrete loader type behind the
+// interface, as middleware wrapping a MultiTenantLoader would.
+type wrappedMultiTenantLoader[E any, P any] struct {
+	MultiTenantLoader[E, P]
+}
+
+func TestMultiTenantHealthCheck_WrappedLoader(t *testing.T) {
+	mock := NewMockProvider(map[string]string{"REGION": "us-east-1"})
+	for _, code := range []string{"a", "b"} {
+		mock.SetProjectValues("", code, map[string]string{"PROJECT_NAME": code})
+	}
+	provider := &partialFailureProvider{MockProvider: mock, fail: map[string]bool{"b": true}}
+	inner := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](provider, nil)
+	l := wrappedMultiTenantLoader[MTEnvConfig, MTProjectConfig]{inner}
+	ctx := context.Background()
+
+	if _, err := l.LoadEnv(ctx); err != nil {
+		t.Fatalf("LoadEnv failed: %v", err)
+	}
+	l.LoadProject(ctx, "a")
+	l.LoadProject(ctx, "b")
+
+	err := MultiTenantHealthCheck[MTEnvConfig, MTProjectConfig](l, 0)(ctx)
+	var the *TenantHealthError
+	if !errors.As(err, &the) {
+		t.Fatalf("health error = %v, want *TenantHealthError", err)
+	}
+	if _, ok := the.Failed["b"]; !ok || the.Expected != 2 {
+		t.Errorf("Failed = %v, Expected = %d, want tenant b of 2", the.Failed, the.Expected)
+	}
+}
+
+// writeTestFallback writes a fallback JSON file to a temp dir and returns its path.
+func writeTestFallback(t *testing.T, body string) string {
+	t.Helper()
+	path := t.TempDir() + "/fallback.json"
+	if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	return path
+}
+
 // newTestDopplerServer creates a test HTTP server that returns the given body.
 func newTestDopplerServer(t *testing.T, body string, statusCode int) *httpTestServer {
 	t.Helper()
@@ -1,7 +1,10 @@
 package dopplerconfig
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -33,6 +36,11 @@ type BootstrapConfig struct {
 
 	// FailurePolicy controls behavior when Doppler is unavailable.
 	FailurePolicy FailurePolicy
+
+	// Offline forces fallback-only mode (DOPPLER_OFFLINE). When set, no
+	// DopplerProvider is created even if a token is present, and a
+	// FallbackPath is required. Intended for air-gapped deploys.
+	Offline bool
 }
 
 // FailurePolicy defines how to handle Doppler unavailability.
@@ -59,6 +67,7 @@ func LoadBootstrapFromEnv() BootstrapConfig {
 		WatchEnabled:  os.Getenv("DOPPLER_WATCH_ENABLED") == "true",
 		WatchInterval: 30 * time.Second,
 		FailurePolicy: FailurePolicyFallback,
+		Offline:       os.Getenv("DOPPLER_OFFLINE") == "true",
 	}
 
 	// Parse failure policy
@@ -74,9 +83,10 @@ func LoadBootstrapFromEnv() BootstrapConfig {
 	return cfg
 }
 
-// IsEnabled returns true if Doppler integration is enabled (token is set).
+// IsEnabled returns true if Doppler integration is enabled (token is set
+// and offline mode is not forced).
 func (b BootstrapConfig) IsEnabled() bool {
-	return b.Token != ""
+	return b.Token != "" && !b.Offline
 }
 
 // HasFallback returns true if a fallback path is configured.
@@ -87,6 +97,10 @@ func (b BootstrapConfig) HasFallback() bool {
 // Struct tag constants for config mapping.
 const (
 	// TagDoppler is the struct tag for mapping to Doppler key names.
+	// A field tagged doppler:"-" is never populated from config. On a slice
+	// field, a "#" in the key reads numbered keys into the elements:
+	// doppler:"UPSTREAM_#" takes UPSTREAM_1, UPSTREAM_2, ... up to the
+	// first gap.
 	// Example: `doppler:"REDIS_PASSWORD"`
 	TagDoppler = "doppler"
 
@@ -114,6 +128,54 @@ const (
 	// TagDescription provides documentation for the field.
 	// Example: `description:"gRPC server port"`
 	TagDescription = "description"
+
+	// TagEncoding selects how a single value is decoded into the field.
+	// "json" decodes the value with json.Unmarshal, for fields that hold a
+	// whole JSON object or array in one key.
+	// Example: `encoding:"json"`
+	TagEncoding = "encoding"
+
+	// TagRequiredGroup names a group of sibling fields of which exactly one
+	// must be set (non-zero). Adding ",atleast" on any member relaxes the
+	// group to at least one.
+	// Example: `required_group:"db"` or `required_group:"db,atleast"`
+	TagRequiredGroup = "required_group"
+
+	// TagMutexGroup names a group of mutually exclusive sibling fields: at
+	// most one of them may be set (non-zero).
+	// Example: `mutex_group:"auth"`
+	TagMutexGroup = "mutex_group"
+
+	// TagOptional marks a nested struct field as an optional section: if
+	// every field in it is zero the section is not validated, so its
+	// required children are only enforced once any of them is set. Values
+	// filled from default tags count as set.
+	// Example: `optional:"true"`
+	TagOptional = "optional"
+
+	// TagProfile gives per-config defaults, chosen by the active Doppler
+	// config name (BootstrapConfig.Config, or WithProfile). A branch config
+	// such as "dev_alice" also matches its root's entry. When no entry
+	// matches, the default tag applies.
+	// Example: `profile:"dev=debug;prd=warn" default:"info"`
+	TagProfile = "profile"
+
+	// TagCollect set to "prefix" turns the field's doppler tag into a key
+	// prefix: every key starting with it is gathered into the field, which
+	// must be a map with string keys, under the lowercased remainder.
+	// Example: `doppler:"WEBHOOK_URL_" collect:"prefix"`
+	TagCollect = "collect"
+)
+
+// ProviderRole identifies which of a loader's providers served a config.
+type ProviderRole string
+
+const (
+	// RolePrimary means the primary provider served the values.
+	RolePrimary ProviderRole = "primary"
+
+	// RoleFallback means the fallback provider served the values.
+	RoleFallback ProviderRole = "fallback"
 )
 
 // ConfigMetadata contains information about a loaded configuration.
@@ -121,6 +183,12 @@ type ConfigMetadata struct {
 	// Source indicates where the config was loaded from.
 	Source string
 
+	// Role is which of the loader's providers served the values:
+	// RolePrimary or RoleFallback, or empty when none did and struct
+	// defaults are in use. Unlike Source it stays accurate when both
+	// providers report the same Name.
+	Role ProviderRole
+
 	// LoadedAt is when the config was loaded.
 	LoadedAt time.Time
 
@@ -130,6 +198,17 @@ type ConfigMetadata struct {
 	// Config is the Doppler config name (if applicable).
 	Config string
 
+	// EffectiveConfig is the config that actually served the values, as
+	// reported by Doppler. It differs from Config when Doppler resolves the
+	// request to another config, such as an inherited root; when the API
+	// does not report one it equals Config.
+	EffectiveConfig string
+
+	// Version is the loader's config version: it increases each time a
+	// load applies content that differs from the previous config. See
+	// Loader.Version.
+	Version uint64
+
 	// ETag is the version identifier from Doppler (for caching).
 	ETag string
 
@@ -140,6 +219,30 @@ type ConfigMetadata struct {
 	Warnings []string
 }
 
+// String returns a one-line summary for startup logs, e.g.
+//
+//	source=doppler project=app config=prd keys=42 loaded=2024-01-01T00:00:00Z warnings=0
+//
+// Only the number of warnings is included, not their text. Values that are
+// empty or contain spaces are quoted. An effective_config field follows
+// config when EffectiveConfig differs from it.
+func (m ConfigMetadata) String() string {
+	config := logfmtValue(m.Config)
+	if m.EffectiveConfig != "" && m.EffectiveConfig != m.Config {
+		config += " effective_config=" + logfmtValue(m.EffectiveConfig)
+	}
+	return fmt.Sprintf("source=%s project=%s config=%s keys=%d loaded=%s warnings=%d",
+		logfmtValue(m.Source), logfmtValue(m.Project), config,
+		m.KeyCount, m.LoadedAt.UTC().Format(time.RFC3339), len(m.Warnings))
+}
+
+func logfmtValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
 // SecretValue wraps a string value that should be redacted in logs.
 type SecretValue struct {
 	value string
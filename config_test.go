@@ -3,6 +3,7 @@ package dopplerconfig
 import (
 	"encoding/json"
 	"testing"
+	"time"
 )
 
 func TestLoadBootstrapFromEnv(t *testing.T) {
@@ -69,6 +70,24 @@ func TestBootstrapConfig_IsEnabled(t *testing.T) {
 	if cfg2.IsEnabled() {
 		t.Error("IsEnabled() = true, want false when token is empty")
 	}
+
+	cfg3 := BootstrapConfig{Token: "some-token", Offline: true}
+	if cfg3.IsEnabled() {
+		t.Error("IsEnabled() = true, want false when offline mode is set")
+	}
+}
+
+func TestLoadBootstrapFromEnv_Offline(t *testing.T) {
+	t.Setenv("DOPPLER_TOKEN", "dp.st.test-token")
+	t.Setenv("DOPPLER_OFFLINE", "true")
+
+	cfg := LoadBootstrapFromEnv()
+	if !cfg.Offline {
+		t.Error("Offline = false, want true when DOPPLER_OFFLINE=true")
+	}
+	if cfg.IsEnabled() {
+		t.Error("IsEnabled() = true, want false in offline mode")
+	}
 }
 
 func TestBootstrapConfig_HasFallback(t *testing.T) {
@@ -107,3 +126,32 @@ func TestNewSecretValue(t *testing.T) {
 		t.Errorf("Value() = %q, want %q", sv.Value(), "my-secret")
 	}
 }
+
+func TestConfigMetadata_String(t *testing.T) {
+	meta := ConfigMetadata{
+		Source:   "doppler",
+		LoadedAt: time.Date(2024, 1, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600)),
+		Project:  "app",
+		Config:   "prd",
+		ETag:     "W/\"abc\"",
+		KeyCount: 42,
+		Warnings: []string{"failed to set Port: invalid syntax", "unused key LEGACY"},
+	}
+
+	want := "source=doppler project=app config=prd keys=42 loaded=2024-01-01T00:00:00Z warnings=2"
+	if got := meta.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	empty := ConfigMetadata{Source: "file:/etc/my app.json"}
+	want = `source="file:/etc/my app.json" project="" config="" keys=0 loaded=0001-01-01T00:00:00Z warnings=0`
+	if got := empty.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	meta.EffectiveConfig = "prd_root"
+	want = "source=doppler project=app config=prd effective_config=prd_root keys=42 loaded=2024-01-01T00:00:00Z warnings=2"
+	if got := meta.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package dopplerconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DirProvider reads a directory holding one file per key, the layout of a
+// Kubernetes secret or configmap volume (/etc/secrets/DB_PASSWORD): each
+// regular file becomes a value keyed by its file name, with surrounding
+// whitespace trimmed from its contents. Symlinks are followed, so the
+// "..data" indirection Kubernetes uses for atomic updates resolves to the
+// current files, while the hidden "..data" and timestamped directories
+// themselves are skipped like any other directory. Names starting with "."
+// are ignored. Keys pass the same dangerous-key checks as FileProvider.
+type DirProvider struct {
+	dir  string
+	glob string
+	name string
+}
+
+// NewDirProvider creates a provider for the files in dir.
+func NewDirProvider(dir string) *DirProvider {
+	return &DirProvider{dir: dir}
+}
+
+// WithGlob limits the provider to file names matching pattern, in
+// filepath.Match syntax, e.g. "DB_*". It returns p for chaining.
+func (p *DirProvider) WithGlob(pattern string) *DirProvider {
+	p.glob = pattern
+	return p
+}
+
+// WithName sets the name reported by Name in place of "dir:<path>". It
+// returns p for chaining.
+func (p *DirProvider) WithName(name string) *DirProvider {
+	p.name = name
+	return p
+}
+
+// Fetch reads every matching file in the directory.
+func (p *DirProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	return p.FetchProject(ctx, "", "")
+}
+
+// FetchProject reads the directory. The project/config parameters are
+// ignored.
+func (p *DirProvider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, p.error(ProviderErrorUnavailable, "secret directory read aborted", err)
+	}
+	if p.glob != "" {
+		if _, err := filepath.Match(p.glob, ""); err != nil {
+			return nil, p.error(ProviderErrorInvalid, fmt.Sprintf("invalid glob %q", p.glob), err)
+		}
+	}
+
+	values, err := readFileValues(p.dir, func(name string) bool {
+		if strings.HasPrefix(name, ".") {
+			return false
+		}
+		if p.glob == "" {
+			return true
+		}
+		ok, _ := filepath.Match(p.glob, name)
+		return ok
+	})
+	if err != nil {
+		return nil, p.error(ProviderErrorUnavailable, "failed to read secret directory", err)
+	}
+	if err := ValidateKeys(values); err != nil {
+		return nil, p.error(ProviderErrorInvalid, "secret file names failed security validation", err)
+	}
+	return values, nil
+}
+
+// readFileValues reads each regular file in dir accepted by include into a
+// map from file name to trimmed contents. Symlinks are followed; entries
+// that resolve to anything other than a regular file are skipped.
+func readFileValues(dir string, include func(name string) bool) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if !include(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if entry.Type()&os.ModeSymlink != 0 {
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+		} else if !entry.Type().IsRegular() {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		values[entry.Name()] = strings.TrimSpace(string(data))
+	}
+	return values, nil
+}
+
+func (p *DirProvider) error(kind ProviderErrorKind, message string, err error) *ProviderError {
+	return &ProviderError{Provider: p.Name(), Kind: kind, Message: message, Err: err}
+}
+
+// Name returns the provider name: the one set with WithName, or
+// "dir:<path>".
+func (p *DirProvider) Name() string {
+	if p.name != "" {
+		return p.name
+	}
+	return "dir:" + p.dir
+}
+
+// Close is a no-op for directory providers.
+func (p *DirProvider) Close() error {
+	return nil
+}
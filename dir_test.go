@@ -0,0 +1,105 @@
+package dopplerconfig
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newK8sSecretDir lays out dir like a Kubernetes secret volume: the files
+// live in a timestamped directory reached through the ..data symlink, and
+// each key is a symlink into ..data.
+func newK8sSecretDir(t *testing.T, values map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeK8sGeneration(t, dir, "..2024_01_01_00_00_00.1", values)
+	for name := range values {
+		if err := os.Symlink(filepath.Join("..data", name), filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// writeK8sGeneration writes values into a new generation directory and
+// points ..data at it, as the kubelet does on a secret update.
+func writeK8sGeneration(t *testing.T, dir, generation string, values map[string]string) {
+	t.Helper()
+	for name, value := range values {
+		writeCredential(t, filepath.Join(dir, generation), name, value)
+	}
+	tmp := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink(generation, tmp); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDirProvider_KubernetesLayout(t *testing.T) {
+	dir := newK8sSecretDir(t, map[string]string{
+		"DB_PASSWORD": "s3cret\n",
+		"API_KEY":     "key-123",
+	})
+	writeCredential(t, dir, ".hidden", "ignored")
+	if err := os.Symlink("missing", filepath.Join(dir, "DANGLING")); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewDirProvider(dir)
+	values, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	want := map[string]string{"DB_PASSWORD": "s3cret", "API_KEY": "key-123"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Fetch = %v, want %v", values, want)
+	}
+
+	writeK8sGeneration(t, dir, "..2024_01_02_00_00_00.2", map[string]string{
+		"DB_PASSWORD": "rotated",
+		"API_KEY":     "key-456",
+	})
+	values, err = p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch after update failed: %v", err)
+	}
+	if values["DB_PASSWORD"] != "rotated" || values["API_KEY"] != "key-456" {
+		t.Errorf("Fetch after update = %v, want the new generation", values)
+	}
+
+	values, err = NewDirProvider(dir).WithGlob("DB_*").Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch with glob failed: %v", err)
+	}
+	if !reflect.DeepEqual(values, map[string]string{"DB_PASSWORD": "rotated"}) {
+		t.Errorf("Fetch with glob DB_* = %v", values)
+	}
+	if got := p.Name(); got != "dir:"+dir {
+		t.Errorf("Name() = %q, want dir:%s", got, dir)
+	}
+}
+
+func TestDirProvider_Errors(t *testing.T) {
+	var perr *ProviderError
+	_, err := NewDirProvider(filepath.Join(t.TempDir(), "missing")).Fetch(context.Background())
+	if !errors.As(err, &perr) || perr.Kind != ProviderErrorUnavailable {
+		t.Errorf("Fetch of a missing directory error = %v, want ProviderErrorUnavailable", err)
+	}
+
+	_, err = NewDirProvider(t.TempDir()).WithGlob("[").Fetch(context.Background())
+	if !errors.As(err, &perr) || perr.Kind != ProviderErrorInvalid {
+		t.Errorf("Fetch with a bad glob error = %v, want ProviderErrorInvalid", err)
+	}
+
+	dir := t.TempDir()
+	writeCredential(t, dir, "__proto__", "x")
+	_, err = NewDirProvider(dir).Fetch(context.Background())
+	if !errors.As(err, &perr) || perr.Kind != ProviderErrorInvalid {
+		t.Errorf("Fetch with a dangerous file name error = %v, want ProviderErrorInvalid", err)
+	}
+}
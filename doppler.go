@@ -61,19 +61,25 @@
 package dopplerconfig
 
 import (
+	"cmp"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ai8future/chassis-go/v10/call"
 	chassiserrors "github.com/ai8future/chassis-go/v10/errors"
-	"github.com/ai8future/chassis-go/v10/secval"
 )
 
 const (
@@ -115,26 +121,154 @@ type Provider interface {
 	// Name returns a human-readable name for this provider.
 	Name() string
 
-	// Close releases any resources held by the provider.
+	// Close releases any resources held by the provider. It must be safe
+	// to call more than once: loaders and wrapping providers may each
+	// close the same provider, so calls after the first must not release
+	// anything again. Guarding teardown with a sync.Once is the usual way.
 	Close() error
 }
 
+// KeyFetcher is implemented by providers that can fetch a few keys more
+// cheaply than the whole config. Watcher.WatchSubset uses it when
+// available. Keys the source does not have are left out of the result.
+type KeyFetcher interface {
+	FetchKeys(ctx context.Context, keys []string) (map[string]string, error)
+}
+
+// NameFetcher is implemented by providers that can list their keys without
+// fetching values, such as DopplerProvider. Loader.VerifyKeys uses it when
+// available.
+type NameFetcher interface {
+	FetchNames(ctx context.Context) ([]string, error)
+}
+
+// FetchResult is the outcome of a fetch made through ResultFetcher.
+type FetchResult struct {
+	// Values holds the fetched configuration, as returned by Fetch.
+	Values map[string]string
+
+	// NotModified is true if Values are unchanged since the provider's
+	// previous successful fetch, e.g. because Doppler answered 304 Not
+	// Modified and the cached values were served.
+	NotModified bool
+}
+
+// ResultFetcher is implemented by providers that can tell whether the
+// values they return have changed since their previous fetch. The loader
+// calls FetchResult instead of Fetch when it is available, and keeps the
+// current config without re-parsing it when NotModified is set.
+type ResultFetcher interface {
+	FetchResult(ctx context.Context) (FetchResult, error)
+}
+
 // DopplerProvider fetches configuration directly from the Doppler API.
 // It uses chassis-go's call.Client for automatic retries with exponential
 // backoff and circuit breaking to handle transient Doppler API failures.
+//
+// Concurrent fetches of the same project/config share one API request, made
+// with the context of the caller that started it. The others wait for it,
+// each until its own ctx ends. If the starting caller's ctx ends first, the
+// request is aborted and every waiter whose ctx is still live retries,
+// sharing a new request, so no caller gets another caller's cancellation.
+// Close aborts shared requests for all callers.
 type DopplerProvider struct {
-	token   string
-	project string
-	config  string
-	apiURL  string
-	client  httpDoer
-	breaker *call.CircuitBreaker
-	logger  *slog.Logger
-	mu      sync.RWMutex
-	cache   map[string]string
-	etag    string
+	token     string
+	project   string
+	config    string
+	apiURL    string
+	client    httpDoer
+	breaker   *call.CircuitBreaker
+	logger    *slog.Logger
+	mu        sync.RWMutex
+	cache     map[string]string
+	etag      string
+	effective string
+	lastFetch FetchStats
+
+	// contextFields extracts request-scoped log attributes from a fetch's
+	// context; see WithContextFields.
+	contextFields func(ctx context.Context) []slog.Attr
+
+	// correlationBase identifies this provider in per-fetch correlation
+	// ids; empty unless WithCorrelationID is set.
+	correlationBase string
+	correlationSeq  atomic.Uint64
+
+	// circuitMu guards the breaker state recorded after the last request,
+	// against which OnCircuitStateChange transitions are detected.
+	circuitMu       sync.Mutex
+	circuitState    call.State
+	onCircuitChange []func(old, new call.State)
+
+	// closeCtx is cancelled by Close, aborting in-flight requests.
+	closeCtx    context.Context
+	closeCancel context.CancelFunc
+
+	// flights holds the in-flight request per project/config, shared by
+	// concurrent identical fetches.
+	flightMu sync.Mutex
+	flights  map[flightKey]*flight
+}
+
+// flightKey identifies a Doppler project/config fetch.
+type flightKey struct {
+	project, config string
+}
+
+// flight is a fetch in progress; done is closed once values and err are set.
+type flight struct {
+	done   chan struct{}
+	result FetchResult
+	err    error
+
+	// leaderDone records that the ctx of the caller making the request
+	// ended before it finished, so err is that caller's, not the others'.
+	leaderDone bool
+
+	// waiters counts the callers that joined the flight after it started;
+	// guarded by DopplerProvider.flightMu.
+	waiters int
 }
 
+// FetchStats describes the most recent successful fetch made by a
+// DopplerProvider. It is intended for diagnostics (e.g. a /debug/config
+// endpoint) and does not influence fetch behavior.
+type FetchStats struct {
+	// At is when the fetch completed.
+	At time.Time
+
+	// CacheHit is true if Doppler returned 304 Not Modified and the
+	// cached values were served.
+	CacheHit bool
+
+	// KeyCount is the number of keys returned.
+	KeyCount int
+
+	// ETag is the ETag held by the provider after the fetch.
+	ETag string
+
+	// StatusCode is the HTTP status code returned by Doppler.
+	StatusCode int
+
+	// EffectiveConfig is the config Doppler reports as having served the
+	// values, taken from the Doppler-Config response header or, failing
+	// that, the DOPPLER_CONFIG secret Doppler includes in every config. It
+	// is the requested config when Doppler reports neither.
+	EffectiveConfig string
+
+	// CorrelationID is the id sent with the fetch, if WithCorrelationID
+	// is set.
+	CorrelationID string
+}
+
+// dopplerConfigHeader is the response header carrying the config that
+// served a secrets request.
+const dopplerConfigHeader = "Doppler-Config"
+
+// correlationIDHeader is the request header carrying the per-fetch
+// correlation id set up by WithCorrelationID.
+const correlationIDHeader = "X-Correlation-ID"
+
 // DopplerProviderOption configures a DopplerProvider.
 type DopplerProviderOption func(*DopplerProvider)
 
@@ -166,6 +300,35 @@ func WithProviderLogger(logger *slog.Logger) DopplerProviderOption {
 	}
 }
 
+// WithCorrelationID tags every fetch with a correlation id, so Doppler
+// audit logs can be matched to the process that read the secrets. The id
+// is base followed by a per-fetch sequence number ("base-1", "base-2",
+// ...); with an empty base a random one is generated per provider. It is
+// sent in the X-Correlation-ID header and logged at info level with the
+// project, config and key count of every successful fetch.
+func WithCorrelationID(base string) DopplerProviderOption {
+	return func(p *DopplerProvider) {
+		if base == "" {
+			b := make([]byte, 8)
+			rand.Read(b)
+			base = hex.EncodeToString(b)
+		}
+		p.correlationBase = base
+	}
+}
+
+// WithContextFields adds the attributes fn extracts from a fetch's context,
+// such as a trace or request id, to every log line for that fetch, so a
+// failed fetch can be correlated with the request that triggered it. By
+// default no context values are logged. fn must be safe for concurrent use;
+// concurrent identical fetches share one request and log the first
+// caller's attributes.
+func WithContextFields(fn func(ctx context.Context) []slog.Attr) DopplerProviderOption {
+	return func(p *DopplerProvider) {
+		p.contextFields = fn
+	}
+}
+
 // WithCallOptions configures the underlying chassis-go call.Client
 // with custom options (timeout, retry, circuit breaker settings).
 // This replaces the default call.Client configuration.
@@ -208,6 +371,11 @@ func NewDopplerProvider(token, project, config string, opts ...DopplerProviderOp
 	for _, opt := range opts {
 		opt(p)
 	}
+	if p.breaker != nil {
+		p.circuitState = p.breaker.State()
+	}
+
+	p.closeCtx, p.closeCancel = context.WithCancel(context.Background())
 
 	return p, nil
 }
@@ -228,6 +396,43 @@ func (p *DopplerProvider) CircuitState() call.State {
 	return p.breaker.State()
 }
 
+// OnCircuitStateChange registers fn to be called when a request made by the
+// provider finds the circuit breaker in a different state than the previous
+// request left it, e.g. closed→open once Doppler becomes unreachable, and
+// open→closed once a request succeeds again. Each transition is reported
+// once, even when concurrent fetches observe it. fn is never called when no
+// breaker is configured (e.g. with WithHTTPClient).
+func (p *DopplerProvider) OnCircuitStateChange(fn func(old, new call.State)) {
+	p.circuitMu.Lock()
+	defer p.circuitMu.Unlock()
+	p.onCircuitChange = append(p.onCircuitChange, fn)
+}
+
+// do sends req, then reports a circuit breaker transition if the breaker's
+// state differs from the one recorded after the previous request.
+func (p *DopplerProvider) do(req *http.Request) (*http.Response, error) {
+	resp, err := p.client.Do(req)
+	if p.breaker == nil {
+		return resp, err
+	}
+
+	state := p.breaker.State()
+	p.circuitMu.Lock()
+	old := p.circuitState
+	if state == old {
+		p.circuitMu.Unlock()
+		return resp, err
+	}
+	p.circuitState = state
+	callbacks := slices.Clone(p.onCircuitChange)
+	p.circuitMu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(old, state)
+	}
+	return resp, err
+}
+
 // dopplerSecretsResponse is the response from Doppler's /secrets endpoint.
 type dopplerSecretsResponse struct {
 	Secrets map[string]struct {
@@ -240,13 +445,179 @@ func (p *DopplerProvider) Fetch(ctx context.Context) (map[string]string, error)
 	return p.FetchProject(ctx, p.project, p.config)
 }
 
+// FetchResult implements ResultFetcher. It fetches like Fetch and sets
+// NotModified when Doppler answered 304 Not Modified.
+func (p *DopplerProvider) FetchResult(ctx context.Context) (FetchResult, error) {
+	return p.fetchShared(ctx, p.project, p.config)
+}
+
 // FetchProject retrieves secrets for a specific project/config.
+//
+// Concurrent calls for the same project/config share one API request; each
+// caller gets its own copy of the result. A caller whose ctx ends while
+// waiting on another caller's request returns ctx.Err(); see
+// DopplerProvider for what happens when the other caller's ctx ends.
 func (p *DopplerProvider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	result, err := p.fetchShared(ctx, project, config)
+	if err != nil {
+		return nil, err
+	}
+	return result.Values, nil
+}
+
+// fetchShared makes or joins the API request for project/config, giving
+// the caller its own copy of the values. A joined request aborted because
+// its leader's ctx ended is retried while the caller's own ctx and the
+// provider are live.
+func (p *DopplerProvider) fetchShared(ctx context.Context, project, config string) (FetchResult, error) {
+	key := flightKey{project: project, config: config}
+
+	for {
+		p.flightMu.Lock()
+		f, ok := p.flights[key]
+		if !ok {
+			break // make the request, still holding flightMu
+		}
+		f.waiters++
+		p.flightMu.Unlock()
+
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return FetchResult{}, ctx.Err()
+		}
+		if f.leaderDone && f.err != nil && ctx.Err() == nil && p.closeCtx.Err() == nil {
+			continue
+		}
+		return f.copyResult()
+	}
+
+	f := &flight{done: make(chan struct{})}
+	if p.flights == nil {
+		p.flights = make(map[flightKey]*flight)
+	}
+	p.flights[key] = f
+	p.flightMu.Unlock()
+
+	f.result, f.err = p.fetchProject(ctx, project, config)
+	f.leaderDone = ctx.Err() != nil
+
+	p.flightMu.Lock()
+	delete(p.flights, key)
+	p.flightMu.Unlock()
+	close(f.done)
+
+	return f.copyResult()
+}
+
+// copyResult returns the outcome of a finished flight with its own copy of
+// the values.
+func (f *flight) copyResult() (FetchResult, error) {
+	if f.err != nil {
+		return FetchResult{}, f.err
+	}
+	result := f.result
+	result.Values = maps.Clone(result.Values)
+	return result, nil
+}
+
+// FetchNames lists the names of the secrets in the configured
+// project/config without their values, using Doppler's names endpoint. It
+// is meant for cheap pre-flight checks such as Loader.VerifyKeys; the
+// result is not cached and does not affect the ETag cache.
+func (p *DopplerProvider) FetchNames(ctx context.Context) ([]string, error) {
+	ctx, cancel := mergeCancel(ctx, p.closeCtx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+"/configs/config/secrets/names", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	q := req.URL.Query()
+	if p.project != "" {
+		q.Add("project", p.project)
+	}
+	if p.config != "" {
+		q.Add("config", p.config)
+	}
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("doppler API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newDopplerError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read doppler response: %w", err)
+	}
+	if err := validateJSON(body); err != nil {
+		return nil, fmt.Errorf("doppler response security validation failed: %w", err)
+	}
+	var namesResp struct {
+		Names []string `json:"names"`
+	}
+	if err := json.Unmarshal(body, &namesResp); err != nil {
+		return nil, fmt.Errorf("failed to decode doppler response: %w", err)
+	}
+	return namesResp.Names, nil
+}
+
+// newDopplerError builds the error for a non-200 API response, keeping at
+// most 1KB of the body to limit memory use and exposure.
+func newDopplerError(resp *http.Response) *DopplerError {
+	const maxErrorBodySize = 1024
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	rawBody := string(body)
+	if len(rawBody) >= maxErrorBodySize {
+		rawBody = rawBody[:maxErrorBodySize-3] + "..."
+	}
+	return &DopplerError{
+		StatusCode: resp.StatusCode,
+		Message:    fmt.Sprintf("API returned status %d", resp.StatusCode),
+		Raw:        rawBody,
+	}
+}
+
+// FetchBranch retrieves secrets for a branch config of project/config
+// without a separate provider. Doppler names branch configs after their
+// root config, so branch "feature-x" of "dev" is requested as config
+// "dev_feature-x"; a branch already carrying the "dev_" prefix is used as
+// is. An empty branch fetches config itself. Fetch keeps using the config
+// given to NewDopplerProvider.
+func (p *DopplerProvider) FetchBranch(ctx context.Context, project, config, branch string) (map[string]string, error) {
+	return p.FetchProject(ctx, project, branchConfig(config, branch))
+}
+
+// branchConfig returns the Doppler config name of branch under root config.
+func branchConfig(config, branch string) string {
+	switch {
+	case branch == "":
+		return config
+	case strings.HasPrefix(branch, config+"_"):
+		return branch
+	}
+	return config + "_" + branch
+}
+
+// fetchProject makes the API request for FetchProject.
+func (p *DopplerProvider) fetchProject(ctx context.Context, project, config string) (FetchResult, error) {
+	logger := p.requestLogger(ctx)
+	ctx, cancel := mergeCancel(ctx, p.closeCtx)
+	defer cancel()
+
 	url := fmt.Sprintf("%s/configs/config/secrets", p.apiURL)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return FetchResult{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Add query parameters
@@ -263,6 +634,12 @@ func (p *DopplerProvider) FetchProject(ctx context.Context, project, config stri
 	req.Header.Set("Authorization", "Bearer "+p.token)
 	req.Header.Set("Accept", "application/json")
 
+	var correlationID string
+	if p.correlationBase != "" {
+		correlationID = fmt.Sprintf("%s-%d", p.correlationBase, p.correlationSeq.Add(1))
+		req.Header.Set(correlationIDHeader, correlationID)
+	}
+
 	// Add ETag for caching if available
 	p.mu.RLock()
 	if p.etag != "" {
@@ -270,65 +647,63 @@ func (p *DopplerProvider) FetchProject(ctx context.Context, project, config stri
 	}
 	p.mu.RUnlock()
 
-	resp, err := p.client.Do(req)
+	resp, err := p.do(req)
 	if err != nil {
-		p.logger.Warn("doppler API request failed",
+		logger.Warn("doppler API request failed",
 			"error", err,
 			"project", project,
 			"config", config,
 		)
-		return nil, fmt.Errorf("doppler API request failed: %w", err)
+		return FetchResult{}, fmt.Errorf("doppler API request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Handle not modified (cache hit)
 	if resp.StatusCode == http.StatusNotModified {
-		p.logger.Debug("doppler cache hit (ETag match)",
+		logger.Debug("doppler cache hit (ETag match)",
 			"project", project,
 			"config", config,
 		)
-		p.mu.RLock()
+		p.mu.Lock()
 		cached := make(map[string]string, len(p.cache))
 		for k, v := range p.cache {
 			cached[k] = v
 		}
-		p.mu.RUnlock()
-		return cached, nil
+		p.lastFetch = FetchStats{
+			At:              time.Now(),
+			CacheHit:        true,
+			KeyCount:        len(cached),
+			ETag:            p.etag,
+			StatusCode:      resp.StatusCode,
+			EffectiveConfig: cmp.Or(p.effective, config),
+			CorrelationID:   correlationID,
+		}
+		p.mu.Unlock()
+		p.logFetch(logger, correlationID, project, config, len(cached), true)
+		return FetchResult{Values: cached, NotModified: true}, nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		// Limit error body read to 1KB to prevent memory issues and limit exposure
-		const maxErrorBodySize = 1024
-		limitedReader := io.LimitReader(resp.Body, maxErrorBodySize)
-		body, _ := io.ReadAll(limitedReader)
-		rawBody := string(body)
-		if len(rawBody) >= maxErrorBodySize {
-			rawBody = rawBody[:maxErrorBodySize-3] + "..."
-		}
-		return nil, &DopplerError{
-			StatusCode: resp.StatusCode,
-			Message:    fmt.Sprintf("API returned status %d", resp.StatusCode),
-			Raw:        rawBody,
-		}
+		return FetchResult{}, newDopplerError(resp)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read doppler response: %w", err)
+		return FetchResult{}, fmt.Errorf("failed to read doppler response: %w", err)
 	}
 
-	if err := secval.ValidateJSON(body); err != nil {
-		p.logger.Error("doppler response failed security validation",
+	if err := validateJSON(body); err != nil {
+		logger.Error("doppler response failed security validation",
 			"error", err,
 			"project", project,
 			"config", config,
 		)
-		return nil, fmt.Errorf("doppler response security validation failed: %w", err)
+		return FetchResult{}, fmt.Errorf("doppler response security validation failed: %w", err)
 	}
 
 	var dopplerResp dopplerSecretsResponse
 	if err := json.Unmarshal(body, &dopplerResp); err != nil {
-		return nil, fmt.Errorf("failed to decode doppler response: %w", err)
+		return FetchResult{}, fmt.Errorf("failed to decode doppler response: %w", err)
 	}
 
 	// Extract raw values
@@ -336,6 +711,9 @@ func (p *DopplerProvider) FetchProject(ctx context.Context, project, config stri
 	for k, v := range dopplerResp.Secrets {
 		result[k] = v.Raw
 	}
+	if err := checkKeyPolicy(result); err != nil {
+		return FetchResult{}, fmt.Errorf("doppler response security validation failed: %w", err)
+	}
 
 	// Update cache with new ETag
 	p.mu.Lock()
@@ -343,9 +721,70 @@ func (p *DopplerProvider) FetchProject(ctx context.Context, project, config stri
 	if etag := resp.Header.Get("ETag"); etag != "" {
 		p.etag = etag
 	}
+	p.effective = cmp.Or(resp.Header.Get(dopplerConfigHeader), result["DOPPLER_CONFIG"], config)
+	p.lastFetch = FetchStats{
+		At:              time.Now(),
+		KeyCount:        len(result),
+		ETag:            p.etag,
+		StatusCode:      resp.StatusCode,
+		EffectiveConfig: p.effective,
+		CorrelationID:   correlationID,
+	}
 	p.mu.Unlock()
+	p.logFetch(logger, correlationID, project, config, len(result), false)
 
-	return result, nil
+	return FetchResult{Values: result}, nil
+}
+
+// requestLogger returns the provider's logger with the attributes of
+// WithContextFields added for ctx.
+func (p *DopplerProvider) requestLogger(ctx context.Context) *slog.Logger {
+	if p.contextFields == nil {
+		return p.logger
+	}
+	attrs := p.contextFields(ctx)
+	if len(attrs) == 0 {
+		return p.logger
+	}
+	args := make([]any, len(attrs))
+	for i, a := range attrs {
+		args[i] = a
+	}
+	return p.logger.With(args...)
+}
+
+// logFetch records a successful fetch at info level when correlation ids
+// are enabled.
+func (p *DopplerProvider) logFetch(logger *slog.Logger, correlationID, project, config string, keyCount int, cacheHit bool) {
+	if correlationID == "" {
+		return
+	}
+	logger.Info("doppler secrets fetched",
+		"correlation_id", correlationID,
+		"project", project,
+		"config", config,
+		"key_count", keyCount,
+		"cache_hit", cacheHit,
+	)
+}
+
+// InvalidateCache clears the stored ETag and cached values, so the next
+// fetch omits If-None-Match and re-reads every secret. Use it when a secret
+// is known to have changed out of band, e.g. on a webhook-triggered reload.
+func (p *DopplerProvider) InvalidateCache() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.etag = ""
+	p.cache = nil
+	p.effective = ""
+}
+
+// LastFetch returns diagnostics about the most recent successful fetch.
+// The zero value is returned if no fetch has succeeded yet.
+func (p *DopplerProvider) LastFetch() FetchStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lastFetch
 }
 
 // Name returns the provider name.
@@ -353,8 +792,10 @@ func (p *DopplerProvider) Name() string {
 	return "doppler"
 }
 
-// Close releases resources.
+// Close cancels any in-flight requests. Fetches started after Close fail
+// with context.Canceled. Calling Close again has no effect.
 func (p *DopplerProvider) Close() error {
+	p.closeCancel()
 	return nil
 }
 
@@ -373,20 +814,26 @@ func (e *DopplerError) Error() string {
 // with appropriate HTTP/gRPC status codes. This allows Doppler errors to flow
 // through chassis-go error handling pipelines and RFC 9457 problem details.
 func (e *DopplerError) ServiceError() *chassiserrors.ServiceError {
-	var se *chassiserrors.ServiceError
+	return statusServiceError(e.StatusCode, e.Message).
+		WithDetail("doppler_status", fmt.Sprintf("%d", e.StatusCode)).
+		WithCause(e)
+}
+
+// statusServiceError maps an upstream HTTP status code to the matching
+// chassis-go ServiceError kind.
+func statusServiceError(statusCode int, message string) *chassiserrors.ServiceError {
 	switch {
-	case e.StatusCode == 401 || e.StatusCode == 403:
-		se = chassiserrors.UnauthorizedError(e.Message)
-	case e.StatusCode == 404:
-		se = chassiserrors.NotFoundError(e.Message)
-	case e.StatusCode == 429:
-		se = chassiserrors.RateLimitError(e.Message)
-	case e.StatusCode >= 500:
-		se = chassiserrors.DependencyError(e.Message)
+	case statusCode == 401 || statusCode == 403:
+		return chassiserrors.UnauthorizedError(message)
+	case statusCode == 404:
+		return chassiserrors.NotFoundError(message)
+	case statusCode == 429:
+		return chassiserrors.RateLimitError(message)
+	case statusCode >= 500:
+		return chassiserrors.DependencyError(message)
 	default:
-		se = chassiserrors.InternalError(e.Message)
+		return chassiserrors.InternalError(message)
 	}
-	return se.WithDetail("doppler_status", fmt.Sprintf("%d", e.StatusCode)).WithCause(e)
 }
 
 // IsDopplerError checks if an error is a Doppler API error.
@@ -398,3 +845,62 @@ func IsDopplerError(err error) (*DopplerError, bool) {
 	}
 	return nil, false
 }
+
+// ProviderErrorKind classifies a ProviderError.
+type ProviderErrorKind string
+
+const (
+	// ProviderErrorUnavailable means the source could not be reached or
+	// read, e.g. a missing fallback file. It maps to a 503 dependency error.
+	ProviderErrorUnavailable ProviderErrorKind = "unavailable"
+
+	// ProviderErrorInvalid means the source was read but its contents were
+	// rejected, e.g. malformed JSON. It maps to a 500 internal error.
+	ProviderErrorInvalid ProviderErrorKind = "invalid"
+)
+
+// ProviderError is returned by non-Doppler providers such as FileProvider
+// so that config failures can be classified the same way as DopplerError,
+// whatever the source.
+type ProviderError struct {
+	// Provider is the Name of the provider that failed.
+	Provider string
+	Kind     ProviderErrorKind
+	Message  string
+	Err      error
+}
+
+func (e *ProviderError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// ServiceError converts a ProviderError to a chassis-go *errors.ServiceError:
+// ProviderErrorUnavailable becomes a dependency error (503) and anything
+// else an internal error (500).
+func (e *ProviderError) ServiceError() *chassiserrors.ServiceError {
+	var se *chassiserrors.ServiceError
+	if e.Kind == ProviderErrorUnavailable {
+		se = chassiserrors.DependencyError(e.Message)
+	} else {
+		se = chassiserrors.InternalError(e.Message)
+	}
+	return se.WithDetail("provider", e.Provider).WithCause(e)
+}
+
+// IsProviderError checks if an error is a ProviderError.
+// Uses errors.As for proper error chain unwrapping.
+func IsProviderError(err error) (*ProviderError, bool) {
+	var pe *ProviderError
+	if errors.As(err, &pe) {
+		return pe, true
+	}
+	return nil, false
+}
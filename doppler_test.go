@@ -0,0 +1,683 @@
+package dopplerconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newETagDopplerServer returns a test server that serves body with the given
+// ETag, and responds 304 Not Modified when the request carries a matching
+// If-None-Match header.
+func newETagDopplerServer(t *testing.T, body, etag string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDopplerProvider_LastFetch(t *testing.T) {
+	srv := newETagDopplerServer(t, `{"secrets":{"A":{"raw":"1"},"B":{"raw":"2"}}}`, `"v1"`)
+
+	provider, err := NewDopplerProvider("test-token", "proj", "dev",
+		WithAPIURL(srv.URL),
+		WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+
+	if stats := provider.LastFetch(); !stats.At.IsZero() {
+		t.Errorf("LastFetch().At = %v before any fetch, want zero", stats.At)
+	}
+
+	if _, err := provider.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+
+	first := provider.LastFetch()
+	if first.At.IsZero() {
+		t.Error("LastFetch().At should be set after fetch")
+	}
+	if first.CacheHit {
+		t.Error("LastFetch().CacheHit = true on first fetch, want false")
+	}
+	if first.KeyCount != 2 {
+		t.Errorf("LastFetch().KeyCount = %d, want 2", first.KeyCount)
+	}
+	if first.ETag != `"v1"` {
+		t.Errorf("LastFetch().ETag = %q, want %q", first.ETag, `"v1"`)
+	}
+	if first.StatusCode != http.StatusOK {
+		t.Errorf("LastFetch().StatusCode = %d, want 200", first.StatusCode)
+	}
+
+	// Second fetch carries the ETag and gets a 304.
+	values, err := provider.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("second Fetch failed: %v", err)
+	}
+	if values["A"] != "1" {
+		t.Errorf("cached A = %q, want %q", values["A"], "1")
+	}
+
+	second := provider.LastFetch()
+	if !second.CacheHit {
+		t.Error("LastFetch().CacheHit = false after 304, want true")
+	}
+	if second.StatusCode != http.StatusNotModified {
+		t.Errorf("LastFetch().StatusCode = %d, want 304", second.StatusCode)
+	}
+	if second.KeyCount != 2 {
+		t.Errorf("LastFetch().KeyCount = %d, want 2", second.KeyCount)
+	}
+	if second.At.Before(first.At) {
+		t.Error("LastFetch().At should not go backwards")
+	}
+}
+
+func TestDopplerProvider_CloseCancelsFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+
+	provider, err := NewDopplerProvider("test-token", "proj", "dev",
+		WithAPIURL(srv.URL),
+		WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := provider.Fetch(context.Background())
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	provider.Close()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Fetch error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Fetch was not cancelled by Close")
+	}
+}
+
+// waitForFlightWaiters blocks until n callers have joined the in-flight
+// request for proj/dev.
+func waitForFlightWaiters(t *testing.T, p *DopplerProvider, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		p.flightMu.Lock()
+		got := 0
+		if f := p.flights[flightKey{project: "proj", config: "dev"}]; f != nil {
+			got = f.waiters
+		}
+		p.flightMu.Unlock()
+		if got >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d callers joined the shared request, want %d", got, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDopplerProvider_ConcurrentFetchesShareRequest(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Query().Get("config") == "dev" {
+			<-release
+		}
+		w.Write([]byte(`{"secrets":{"KEY":{"raw":"value"}}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	provider, err := NewDopplerProvider("test-token", "proj", "dev",
+		WithAPIURL(srv.URL),
+		WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+
+	const callers = 10
+	results := make([]map[string]string, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			values, err := provider.Fetch(context.Background())
+			if err != nil {
+				t.Errorf("Fetch failed: %v", err)
+				return
+			}
+			// Each caller owns its map.
+			values["KEY"] = fmt.Sprintf("caller-%d", i)
+			results[i] = values
+		}(i)
+	}
+	waitForFlightWaiters(t, provider, callers-1)
+	close(release)
+	wg.Wait()
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+	for i, values := range results {
+		if values["KEY"] != fmt.Sprintf("caller-%d", i) {
+			t.Errorf("caller %d map = %v, was mutated by another caller", i, values)
+		}
+	}
+
+	// A different project/config is not shared.
+	if _, err := provider.FetchProject(context.Background(), "proj", "stg"); err != nil {
+		t.Fatalf("FetchProject failed: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server saw %d requests, want 2", got)
+	}
+}
+
+func TestDopplerProvider_SharedFetchSurvivesLeaderCancel(t *testing.T) {
+	var requests atomic.Int32
+	started := make(chan int32, 2)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- requests.Add(1)
+		select {
+		case <-release:
+			w.Write([]byte(`{"secrets":{"KEY":{"raw":"value"}}}`))
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	provider, err := NewDopplerProvider("test-token", "proj", "dev",
+		WithAPIURL(srv.URL),
+		WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := provider.Fetch(leaderCtx)
+		leaderErr <- err
+	}()
+	<-started
+
+	type result struct {
+		values map[string]string
+		err    error
+	}
+	follower := make(chan result, 1)
+	go func() {
+		values, err := provider.Fetch(context.Background())
+		follower <- result{values, err}
+	}()
+	waitForFlightWaiters(t, provider, 1)
+
+	cancelLeader()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader Fetch error = %v, want context.Canceled", err)
+	}
+
+	// The follower retries with its own live ctx instead of failing.
+	if n := <-started; n != 2 {
+		t.Fatalf("retry was request %d, want 2", n)
+	}
+	close(release)
+	got := <-follower
+	if got.err != nil {
+		t.Fatalf("follower Fetch error = %v, want the retried values", got.err)
+	}
+	if got.values["KEY"] != "value" {
+		t.Errorf("follower values = %v", got.values)
+	}
+}
+
+func TestDopplerProvider_InvalidateCache(t *testing.T) {
+	var mu sync.Mutex
+	var ifNoneMatch []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		mu.Unlock()
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"secrets":{"A":{"raw":"1"}}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	provider, err := NewDopplerProvider("test-token", "proj", "dev",
+		WithAPIURL(srv.URL),
+		WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := provider.Fetch(context.Background()); err != nil {
+			t.Fatalf("Fetch failed: %v", err)
+		}
+	}
+	if !provider.LastFetch().CacheHit {
+		t.Fatal("second fetch should be served from the ETag cache")
+	}
+
+	provider.InvalidateCache()
+	values, err := provider.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch after InvalidateCache failed: %v", err)
+	}
+	if values["A"] != "1" || provider.LastFetch().CacheHit {
+		t.Errorf("Fetch after InvalidateCache = %v (cache hit %v), want a fresh read", values, provider.LastFetch().CacheHit)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"", `"v1"`, ""}
+	if fmt.Sprint(ifNoneMatch) != fmt.Sprint(want) {
+		t.Errorf("If-None-Match headers = %q, want %q", ifNoneMatch, want)
+	}
+}
+
+func TestDopplerProvider_FetchBranch(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		configs []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		configs = append(configs, r.URL.Query().Get("config"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"secrets":{"CONFIG":{"raw":%q}}}`, r.URL.Query().Get("config"))
+	}))
+	defer srv.Close()
+
+	provider, err := NewDopplerProvider("test-token", "proj", "dev",
+		WithAPIURL(srv.URL),
+		WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{"feature-x", "dev_feature-x"},
+		{"dev_hotfix", "dev_hotfix"},
+		{"", "dev"},
+	}
+	for _, tt := range tests {
+		values, err := provider.FetchBranch(ctx, "proj", "dev", tt.branch)
+		if err != nil {
+			t.Fatalf("FetchBranch(%q) failed: %v", tt.branch, err)
+		}
+		if values["CONFIG"] != tt.want {
+			t.Errorf("FetchBranch(%q) fetched config %q, want %q", tt.branch, values["CONFIG"], tt.want)
+		}
+	}
+
+	values, err := provider.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if values["CONFIG"] != "dev" {
+		t.Errorf("Fetch fetched config %q, want dev", values["CONFIG"])
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"dev_feature-x", "dev_hotfix", "dev", "dev"}
+	if fmt.Sprint(configs) != fmt.Sprint(want) {
+		t.Errorf("config query params = %v, want %v", configs, want)
+	}
+}
+
+func TestDopplerProvider_EffectiveConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		body   string
+		want   string
+	}{
+		{"header", "dev_root", `{"secrets":{"A":{"raw":"1"}}}`, "dev_root"},
+		{"body", "", `{"secrets":{"A":{"raw":"1"},"DOPPLER_CONFIG":{"raw":"dev_body"}}}`, "dev_body"},
+		{"requested", "", `{"secrets":{"A":{"raw":"1"}}}`, "dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set("Doppler-Config", tt.header)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			provider, err := NewDopplerProvider("test-token", "proj", "dev",
+				WithAPIURL(srv.URL),
+				WithHTTPClient(srv.Client()),
+			)
+			if err != nil {
+				t.Fatalf("NewDopplerProvider failed: %v", err)
+			}
+			loader := NewLoaderWithProvider[struct {
+				A string `doppler:"A"`
+			}](provider, nil)
+			defer loader.Close()
+
+			if _, err := loader.Load(context.Background()); err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if got := provider.LastFetch().EffectiveConfig; got != tt.want {
+				t.Errorf("LastFetch().EffectiveConfig = %q, want %q", got, tt.want)
+			}
+			if got := loader.Metadata().EffectiveConfig; got != tt.want {
+				t.Errorf("Metadata().EffectiveConfig = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoader_ReloadNotModifiedKeepsConfig(t *testing.T) {
+	srv := newETagDopplerServer(t, `{"secrets":{"A":{"raw":"1"}}}`, `"v1"`)
+
+	provider, err := NewDopplerProvider("test-token", "proj", "dev",
+		WithAPIURL(srv.URL),
+		WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+	type config struct {
+		A string `doppler:"A"`
+	}
+	loader := NewLoaderWithProvider[config](provider, nil)
+	defer loader.Close()
+
+	var changes atomic.Int32
+	loader.OnChange(func(old, new *config) { changes.Add(1) })
+
+	ctx := context.Background()
+	first, err := loader.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	loadedAt := loader.Metadata().LoadedAt
+	if etag := loader.Metadata().ETag; etag != `"v1"` {
+		t.Errorf("Metadata().ETag = %q, want %q", etag, `"v1"`)
+	}
+
+	time.Sleep(time.Millisecond)
+	second, err := loader.Reload(ctx)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if !provider.LastFetch().CacheHit {
+		t.Fatal("expected Reload to be served from the ETag cache")
+	}
+	if second != first {
+		t.Error("Reload after 304 should keep the existing *T")
+	}
+	if loader.Current() != first {
+		t.Error("Current() should still be the first config")
+	}
+	if meta := loader.Metadata(); !meta.LoadedAt.After(loadedAt) {
+		t.Errorf("Metadata().LoadedAt = %v, want after %v", meta.LoadedAt, loadedAt)
+	}
+	if n := changes.Load(); n != 0 {
+		t.Errorf("OnChange called %d times, want 0", n)
+	}
+}
+
+func TestDopplerProvider_FetchResult(t *testing.T) {
+	srv := newETagDopplerServer(t, `{"secrets":{"A":{"raw":"1"}}}`, `"v1"`)
+
+	provider, err := NewDopplerProvider("test-token", "proj", "dev",
+		WithAPIURL(srv.URL),
+		WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+
+	ctx := context.Background()
+	first, err := provider.FetchResult(ctx)
+	if err != nil {
+		t.Fatalf("FetchResult failed: %v", err)
+	}
+	if first.NotModified || first.Values["A"] != "1" {
+		t.Errorf("first FetchResult = %+v, want modified values", first)
+	}
+
+	second, err := provider.FetchResult(ctx)
+	if err != nil {
+		t.Fatalf("FetchResult failed: %v", err)
+	}
+	if !second.NotModified || second.Values["A"] != "1" {
+		t.Errorf("second FetchResult = %+v, want NotModified with cached values", second)
+	}
+}
+
+func TestDopplerProvider_WithCorrelationID(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		ids []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ids = append(ids, r.Header.Get("X-Correlation-ID"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"secrets":{"A":{"raw":"1"},"B":{"raw":"2"}}}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	provider, err := NewDopplerProvider("test-token", "proj", "dev",
+		WithAPIURL(srv.URL),
+		WithHTTPClient(srv.Client()),
+		WithProviderLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+		WithCorrelationID("api-7f3c"),
+	)
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := provider.Fetch(ctx); err != nil {
+			t.Fatalf("Fetch failed: %v", err)
+		}
+	}
+
+	mu.Lock()
+	got := fmt.Sprint(ids)
+	mu.Unlock()
+	if want := "[api-7f3c-1 api-7f3c-2]"; got != want {
+		t.Errorf("X-Correlation-ID headers = %s, want %s", got, want)
+	}
+	if id := provider.LastFetch().CorrelationID; id != "api-7f3c-2" {
+		t.Errorf("LastFetch().CorrelationID = %q, want api-7f3c-2", id)
+	}
+
+	var entries []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		if entry["msg"] == "doppler secrets fetched" {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d fetch log lines, want 2:\n%s", len(entries), buf.String())
+	}
+	last := entries[1]
+	if last["level"] != "INFO" || last["correlation_id"] != "api-7f3c-2" ||
+		last["project"] != "proj" || last["config"] != "dev" || last["key_count"] != float64(2) {
+		t.Errorf("fetch log line = %v, want info with id, project, config and key count", last)
+	}
+}
+
+func TestDopplerProvider_GeneratedCorrelationID(t *testing.T) {
+	a, _ := NewDopplerProvider("test-token", "proj", "dev", WithCorrelationID(""))
+	b, _ := NewDopplerProvider("test-token", "proj", "dev", WithCorrelationID(""))
+	if a.correlationBase == "" || a.correlationBase == b.correlationBase {
+		t.Errorf("generated bases %q and %q, want distinct non-empty ids", a.correlationBase, b.correlationBase)
+	}
+}
+
+type traceIDKey struct{}
+
+func TestDopplerProvider_WithContextFields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close() // every request fails to connect
+
+	var buf bytes.Buffer
+	provider, err := NewDopplerProvider("test-token", "proj", "dev",
+		WithAPIURL(srv.URL),
+		WithHTTPClient(&http.Client{}),
+		WithProviderLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+		WithContextFields(func(ctx context.Context) []slog.Attr {
+			if id, ok := ctx.Value(traceIDKey{}).(string); ok {
+				return []slog.Attr{slog.String("trace_id", id)}
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), traceIDKey{}, "trace-abc")
+	if _, err := provider.FetchProject(ctx, "proj", "dev"); err == nil {
+		t.Fatal("FetchProject should fail against a closed server")
+	}
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode log record %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "doppler API request failed" || record["trace_id"] != "trace-abc" {
+		t.Errorf("log record = %v, want the failure with trace_id=trace-abc", record)
+	}
+
+	buf.Reset()
+	provider.FetchProject(context.Background(), "proj", "dev")
+	if strings.Contains(buf.String(), "trace_id") {
+		t.Errorf("log without a trace id in ctx = %q, want no trace_id", buf.String())
+	}
+}
+func TestLoader_VerifyKeys(t *testing.T) {
+	var namesHits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/configs/config/secrets/names" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		namesHits.Add(1)
+		if got := r.URL.Query().Get("config"); got != "dev" {
+			t.Errorf("config query = %q, want dev", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"names":["A","B"]}`))
+	}))
+	defer srv.Close()
+
+	provider, err := NewDopplerProvider("test-token", "proj", "dev",
+		WithAPIURL(srv.URL),
+		WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+	names, err := provider.FetchNames(context.Background())
+	if err != nil {
+		t.Fatalf("FetchNames failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"A", "B"}) {
+		t.Errorf("FetchNames = %v, want [A B]", names)
+	}
+
+	type config struct {
+		A string `doppler:"A"`
+	}
+	loader := NewLoaderWithProvider[config](provider, nil)
+	defer loader.Close()
+
+	missing, err := loader.VerifyKeys(context.Background(), []string{"A", "C"})
+	if err != nil {
+		t.Fatalf("VerifyKeys failed: %v", err)
+	}
+	if !reflect.DeepEqual(missing, []string{"C"}) {
+		t.Errorf("VerifyKeys = %v, want [C]", missing)
+	}
+	if namesHits.Load() != 2 {
+		t.Errorf("names endpoint hit %d times, want 2", namesHits.Load())
+	}
+	if loader.Current() != nil {
+		t.Error("VerifyKeys should not load a config")
+	}
+
+	// Providers without NameFetcher are listed via Fetch.
+	mock := NewMockProvider(map[string]string{"A": "1"})
+	fallback := NewLoaderWithProvider[config](mock, nil)
+	defer fallback.Close()
+	missing, err = fallback.VerifyKeys(context.Background(), []string{"B", "A", "C"})
+	if err != nil {
+		t.Fatalf("VerifyKeys failed: %v", err)
+	}
+	if !reflect.DeepEqual(missing, []string{"B", "C"}) {
+		t.Errorf("VerifyKeys = %v, want [B C]", missing)
+	}
+}
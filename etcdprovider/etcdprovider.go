@@ -0,0 +1,136 @@
+// Package etcdprovider provides a dopplerconfig.Provider backed by etcd. It
+// lives in its own package so only programs that import it link the etcd
+// client and its gRPC dependencies.
+package etcdprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	chassiserrors "github.com/ai8future/chassis-go/v10/errors"
+	"github.com/ai8future/dopplerconfig"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultSeparator is the separator that replaces "/" in etcd keys,
+// matching the "_" used when flattening nested JSON.
+const DefaultSeparator = "_"
+
+// Provider reads configuration from etcd keys under a prefix.
+//
+// Each key under the prefix becomes a config key with the prefix stripped and
+// the remaining "/" path segments joined with the separator, so with prefix
+// "/config/api/" the key "/config/api/db/host" becomes "db_host".
+//
+// FetchProject narrows the read to sub-prefixes: project and config (when
+// non-empty) are appended as path segments, e.g. "/config/api/<project>/<config>/".
+type Provider struct {
+	kv        clientv3.KV
+	prefix    string
+	separator string
+}
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithSeparator sets the separator that replaces "/" in keys.
+func WithSeparator(sep string) Option {
+	return func(p *Provider) {
+		p.separator = sep
+	}
+}
+
+// New creates a provider reading keys under prefix. kv is usually
+// a *clientv3.Client; the caller owns it and is responsible for closing it.
+func New(kv clientv3.KV, prefix string, opts ...Option) (*Provider, error) {
+	if kv == nil {
+		return nil, fmt.Errorf("etcd client is required")
+	}
+
+	p := &Provider{
+		kv:        kv,
+		prefix:    prefix,
+		separator: DefaultSeparator,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// Fetch reads every key under the provider's prefix.
+func (p *Provider) Fetch(ctx context.Context) (map[string]string, error) {
+	return p.fetchPrefix(ctx, p.prefix)
+}
+
+// FetchProject reads keys under the project and config sub-prefixes.
+func (p *Provider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	prefix := p.prefix
+	for _, segment := range []string{project, config} {
+		if segment == "" {
+			continue
+		}
+		if prefix != "" && !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+		prefix += segment + "/"
+	}
+	return p.fetchPrefix(ctx, prefix)
+}
+
+func (p *Provider) fetchPrefix(ctx context.Context, prefix string) (map[string]string, error) {
+	resp, err := p.kv.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, etcdError(err, prefix)
+	}
+
+	result := make(map[string]string, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		key := strings.Trim(strings.TrimPrefix(string(kv.Key), prefix), "/")
+		if key == "" {
+			continue
+		}
+		result[strings.ReplaceAll(key, "/", p.separator)] = string(kv.Value)
+	}
+
+	if err := dopplerconfig.ValidateKeys(result); err != nil {
+		return nil, fmt.Errorf("etcd security validation failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// etcdError converts etcd gRPC failures into chassis-go ServiceErrors so auth,
+// throttling and availability problems are distinguishable. Context errors
+// and anything without a gRPC status are wrapped unchanged.
+func etcdError(err error, prefix string) error {
+	msg := fmt.Sprintf("etcd get %q failed", prefix)
+
+	var se *chassiserrors.ServiceError
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.PermissionDenied:
+		se = chassiserrors.UnauthorizedError(msg)
+	case codes.ResourceExhausted:
+		se = chassiserrors.RateLimitError(msg)
+	case codes.Unavailable:
+		se = chassiserrors.DependencyError(msg)
+	case codes.DeadlineExceeded:
+		se = chassiserrors.TimeoutError(msg)
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return se.WithDetail("grpc_code", status.Code(err).String()).WithCause(err)
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "etcd:" + p.prefix
+}
+
+// Close is a no-op; the etcd client is owned by the caller.
+func (p *Provider) Close() error {
+	return nil
+}
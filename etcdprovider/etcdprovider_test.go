@@ -0,0 +1,138 @@
+package etcdprovider
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	chassiserrors "github.com/ai8future/chassis-go/v10/errors"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeEtcdKV serves prefix Gets from an in-memory map.
+type fakeEtcdKV struct {
+	clientv3.KV
+	data    map[string]string
+	err     error
+	lastKey string
+}
+
+func (f *fakeEtcdKV) Get(ctx context.Context, key string, _ ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	f.lastKey = key
+	if f.err != nil {
+		return nil, f.err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(f.data))
+	for k := range f.data {
+		if strings.HasPrefix(k, key) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	resp := &clientv3.GetResponse{}
+	for _, k := range keys {
+		resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(k), Value: []byte(f.data[k])})
+	}
+	return resp, nil
+}
+
+func TestProvider_Fetch(t *testing.T) {
+	kv := &fakeEtcdKV{data: map[string]string{
+		"/config/api/PORT":          "8080",
+		"/config/api/db/HOST":       "db.internal",
+		"/config/api/acme/prd/PORT": "9090",
+		"/config/other/PORT":        "1",
+	}}
+	p, err := New(kv, "/config/api/")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	values, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if values["PORT"] != "8080" || values["db_HOST"] != "db.internal" {
+		t.Errorf("values = %v", values)
+	}
+	if _, ok := values["acme_prd_PORT"]; !ok {
+		t.Error("nested keys should be flattened with the separator")
+	}
+
+	values, err = p.FetchProject(context.Background(), "acme", "prd")
+	if err != nil {
+		t.Fatalf("FetchProject failed: %v", err)
+	}
+	if kv.lastKey != "/config/api/acme/prd/" {
+		t.Errorf("Get key = %q, want %q", kv.lastKey, "/config/api/acme/prd/")
+	}
+	if len(values) != 1 || values["PORT"] != "9090" {
+		t.Errorf("project values = %v, want only PORT=9090", values)
+	}
+}
+
+func TestProvider_Separator(t *testing.T) {
+	kv := &fakeEtcdKV{data: map[string]string{"/app/server/port": "80"}}
+	p, _ := New(kv, "/app", WithSeparator("."))
+
+	values, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if values["server.port"] != "80" {
+		t.Errorf("values = %v, want server.port", values)
+	}
+}
+
+func TestProvider_DangerousKey(t *testing.T) {
+	kv := &fakeEtcdKV{data: map[string]string{"/app/__proto__": "x"}}
+	p, _ := New(kv, "/app/")
+
+	if _, err := p.Fetch(context.Background()); err == nil {
+		t.Error("expected dangerous key to be rejected")
+	}
+}
+
+func TestProvider_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantHTTP int
+	}{
+		{"permission denied", status.Error(codes.PermissionDenied, "denied"), 401},
+		{"unavailable", status.Error(codes.Unavailable, "no leader"), 503},
+		{"deadline", status.Error(codes.DeadlineExceeded, "slow"), 504},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := New(&fakeEtcdKV{err: tt.err}, "/app/")
+			_, err := p.Fetch(context.Background())
+
+			var se *chassiserrors.ServiceError
+			if !errors.As(err, &se) {
+				t.Fatalf("error = %v, want *ServiceError", err)
+			}
+			if se.HTTPCode != tt.wantHTTP {
+				t.Errorf("HTTPCode = %d, want %d", se.HTTPCode, tt.wantHTTP)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p, _ := New(&fakeEtcdKV{}, "/app/")
+	if _, err := p.Fetch(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled Fetch = %v, want context.Canceled", err)
+	}
+}
@@ -3,26 +3,62 @@ package dopplerconfig
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
-
-	"github.com/ai8future/chassis-go/v10/secval"
 )
 
-// FileProvider reads configuration from a local JSON file.
+// FileProvider reads configuration from a local JSON file, or a TOML file
+// when the path ends in ".toml". TOML tables and arrays are flattened the
+// same way as JSON objects and arrays.
 // This is used as a fallback when Doppler is unavailable or for local development.
 type FileProvider struct {
-	path string
+	path      string
+	name      string
+	comments  bool
+	expandEnv bool
+
+	// readFile reads the file; it is os.ReadFile outside tests.
+	readFile func(name string) ([]byte, error)
 }
 
 // NewFileProvider creates a new file-based provider.
 func NewFileProvider(path string) *FileProvider {
 	return &FileProvider{
-		path: path,
+		path:     path,
+		readFile: os.ReadFile,
 	}
 }
 
+// WithName sets the name reported by Name, and so by ConfigMetadata.Source
+// and log lines, in place of "file:<path>". It returns p for chaining:
+//
+//	NewFileProvider("config/defaults.json").WithName("defaults")
+func (p *FileProvider) WithName(name string) *FileProvider {
+	p.name = name
+	return p
+}
+
+// WithComments accepts JSONC: `//` line comments and `/* */` block
+// comments are stripped before the file is parsed, leaving comment-like
+// text inside string values untouched. Files are strict JSON by default.
+// It returns p for chaining.
+func (p *FileProvider) WithComments() *FileProvider {
+	p.comments = true
+	return p
+}
+
+// WithEnvExpansion expands $VAR and ${VAR} references in values from the
+// process environment after parsing, e.g. "${HOME}/logs". References to
+// unset variables are left as written, and "$$" yields a literal "$".
+// Expansion is off by default. It returns p for chaining.
+func (p *FileProvider) WithEnvExpansion() *FileProvider {
+	p.expandEnv = true
+	return p
+}
+
 // Fetch reads the JSON file and returns all key-value pairs.
 func (p *FileProvider) Fetch(ctx context.Context) (map[string]string, error) {
 	return p.FetchProject(ctx, "", "")
@@ -30,28 +66,196 @@ func (p *FileProvider) Fetch(ctx context.Context) (map[string]string, error) {
 
 // FetchProject reads the JSON file. The project/config parameters are ignored
 // since file providers don't support multi-project configurations.
+//
+// The read honors ctx: if ctx ends first, for example while a network mount
+// hangs, FetchProject returns an error wrapping ctx.Err() without waiting
+// for the read to finish.
 func (p *FileProvider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
-	data, err := os.ReadFile(p.path)
+	data, err := p.read(ctx)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			return nil, p.error(ProviderErrorUnavailable, "fallback file read aborted", err)
+		}
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("fallback file not found: %s", p.path)
+			return nil, p.error(ProviderErrorUnavailable, fmt.Sprintf("fallback file not found: %s", p.path), err)
+		}
+		return nil, p.error(ProviderErrorUnavailable, "failed to read fallback file", err)
+	}
+
+	var values map[string]string
+	if p.isTOML() {
+		values, err = decodeTOMLValues(data, p.Name(), "fallback file")
+	} else {
+		if p.comments {
+			data = stripJSONComments(data)
+		}
+		values, err = DecodeJSONValues(data, p.Name(), "fallback file")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.expandEnv {
+		for k, v := range values {
+			values[k] = expandEnvSafe(v)
 		}
-		return nil, fmt.Errorf("failed to read fallback file: %w", err)
 	}
+	return values, nil
+}
+
+// isTOML reports whether the file is TOML, by its .toml extension.
+func (p *FileProvider) isTOML() bool {
+	return strings.EqualFold(filepath.Ext(p.path), ".toml")
+}
+
+// expandEnvSafe expands $VAR and ${VAR} from the environment like
+// os.ExpandEnv, except that references to unset variables are kept
+// verbatim and "$$" is an escaped literal "$".
+func expandEnvSafe(s string) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
 
-	if err := secval.ValidateJSON(data); err != nil {
-		return nil, fmt.Errorf("fallback file security validation failed: %w", err)
+		switch next := s[i+1]; {
+		case next == '$':
+			b.WriteByte('$')
+			i++
+		case next == '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				b.WriteString(s[i:])
+				return b.String()
+			}
+			ref := s[i : i+2+end+1]
+			if value, ok := os.LookupEnv(s[i+2 : i+2+end]); ok {
+				b.WriteString(value)
+			} else {
+				b.WriteString(ref)
+			}
+			i += len(ref) - 1
+		case isEnvNameStart(next):
+			j := i + 2
+			for j < len(s) && (isEnvNameStart(s[j]) || (s[j] >= '0' && s[j] <= '9')) {
+				j++
+			}
+			if value, ok := os.LookupEnv(s[i+1 : j]); ok {
+				b.WriteString(value)
+			} else {
+				b.WriteString(s[i:j])
+			}
+			i = j - 1
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String()
+}
+
+func isEnvNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// stripJSONComments replaces `//` and `/* */` comments outside string
+// literals with spaces, keeping newlines so parse errors still point at the
+// right line. An unterminated block comment runs to the end of the input.
+func stripJSONComments(data []byte) []byte {
+	out := make([]byte, len(data))
+	copy(out, data)
+
+	inString := false
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		switch {
+		case inString:
+			if c == '\\' {
+				i++ // skip the escaped character
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			out[i], out[i+1] = ' ', ' '
+			for i += 2; i < len(out); i++ {
+				if out[i] == '*' && i+1 < len(out) && out[i+1] == '/' {
+					out[i], out[i+1] = ' ', ' '
+					i++
+					break
+				}
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+		}
+	}
+	return out
+}
+
+// read reads the file in a goroutine so that a blocked read does not hold
+// up the caller past ctx. An abandoned read finishes in the background.
+func (p *FileProvider) read(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		data, err := p.readFile(p.path)
+		done <- result{data, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.data, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *FileProvider) error(kind ProviderErrorKind, message string, err error) *ProviderError {
+	return &ProviderError{Provider: p.Name(), Kind: kind, Message: message, Err: err}
+}
+
+// DecodeJSONValues validates a JSON config document with secval and the
+// KeyPolicy, then parses and flattens it. Failures are ProviderErrors of
+// kind ProviderErrorInvalid for provider, with what naming the document in
+// the message (e.g. "fallback file"). Providers outside this package that
+// read JSON documents use it to flatten them the same way as FileProvider.
+func DecodeJSONValues(data []byte, provider, what string) (map[string]string, error) {
+	invalid := func(message string, err error) error {
+		return &ProviderError{Provider: provider, Kind: ProviderErrorInvalid, Message: message, Err: err}
+	}
+
+	if err := validateJSON(data); err != nil {
+		return nil, invalid(what+" security validation failed", err)
 	}
 
 	// Parse as flat key-value JSON
 	var values map[string]interface{}
 	if err := json.Unmarshal(data, &values); err != nil {
-		return nil, fmt.Errorf("failed to parse fallback file: %w", err)
+		return nil, invalid("failed to parse "+what, err)
 	}
 
 	// Convert to string map (flattening nested structures)
 	result := make(map[string]string, len(values))
 	flattenJSON("", values, result)
+	if err := checkKeyPolicy(result); err != nil {
+		return nil, invalid(what+" security validation failed", err)
+	}
 
 	return result, nil
 }
@@ -98,8 +302,30 @@ func flattenJSON(prefix string, data map[string]interface{}, result map[string]s
 	}
 }
 
-// Name returns the provider name.
+// MergeValues returns a new map holding base overlaid with override, so
+// override wins for keys present in both. Like the providers, it rejects
+// dangerous keys such as "__proto__" with secval, returning an error if any
+// merged key fails the check. Neither input is modified.
+func MergeValues(base, override map[string]string) (map[string]string, error) {
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	if err := ValidateKeys(merged); err != nil {
+		return nil, fmt.Errorf("merged values failed security validation: %w", err)
+	}
+	return merged, nil
+}
+
+// Name returns the provider name: the one set with WithName, or
+// "file:<path>".
 func (p *FileProvider) Name() string {
+	if p.name != "" {
+		return p.name
+	}
 	return "file:" + p.path
 }
 
@@ -127,6 +353,7 @@ func WriteFallbackFile(path string, values map[string]string) error {
 // This is an alternative to file-based fallback.
 type EnvProvider struct {
 	prefix string
+	name   string
 }
 
 // NewEnvProvider creates a new environment-based provider.
@@ -135,6 +362,13 @@ func NewEnvProvider(prefix string) *EnvProvider {
 	return &EnvProvider{prefix: prefix}
 }
 
+// WithName sets the name reported by Name in place of "env" or
+// "env:<prefix>*". It returns p for chaining.
+func (p *EnvProvider) WithName(name string) *EnvProvider {
+	p.name = name
+	return p
+}
+
 // Fetch reads all environment variables, optionally filtering by prefix.
 func (p *EnvProvider) Fetch(ctx context.Context) (map[string]string, error) {
 	return p.FetchProject(ctx, "", "")
@@ -151,9 +385,38 @@ func (p *EnvProvider) FetchProject(ctx context.Context, project, config string)
 		}
 	}
 
+	if err := p.validate(result); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
+// FetchKeys looks up just the given environment variables, honoring the
+// prefix filter like Fetch.
+func (p *EnvProvider) FetchKeys(ctx context.Context, keys []string) (map[string]string, error) {
+	result := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if p.prefix != "" && !hasPrefix(key, p.prefix) {
+			continue
+		}
+		if value, ok := os.LookupEnv(key); ok {
+			result[key] = value
+		}
+	}
+	if err := p.validate(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validate applies ValidateKeys to variables read from the environment.
+func (p *EnvProvider) validate(values map[string]string) error {
+	if err := ValidateKeys(values); err != nil {
+		return &ProviderError{Provider: p.Name(), Kind: ProviderErrorInvalid, Message: "environment variable names failed security validation", Err: err}
+	}
+	return nil
+}
+
 func splitEnv(env string) (string, string) {
 	for i := 0; i < len(env); i++ {
 		if env[i] == '=' {
@@ -169,6 +432,9 @@ func hasPrefix(s, prefix string) bool {
 
 // Name returns the provider name.
 func (p *EnvProvider) Name() string {
+	if p.name != "" {
+		return p.name
+	}
 	if p.prefix != "" {
 		return "env:" + p.prefix + "*"
 	}
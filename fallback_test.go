@@ -2,9 +2,11 @@ package dopplerconfig
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestFileProvider_Fetch(t *testing.T) {
@@ -79,6 +81,183 @@ func TestFileProvider_Name(t *testing.T) {
 	}
 }
 
+func TestProviders_WithName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "defaults.json")
+	if err := os.WriteFile(path, []byte(`{"SERVER_PORT": "9000", "DATABASE_URL": "postgres://localhost/db"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := NewEnvProvider("APP_").WithName("process-env").Name(); got != "process-env" {
+		t.Errorf("EnvProvider Name() = %q, want %q", got, "process-env")
+	}
+
+	// A failing primary and a named fallback: the custom names flow into
+	// metadata.
+	primary := NewMockProviderWithError(errors.New("down")).WithName("dev-override")
+	if primary.Name() != "dev-override" {
+		t.Errorf("MockProvider Name() = %q, want %q", primary.Name(), "dev-override")
+	}
+	loader := NewLoaderWithProvider[TestConfig](primary, NewFileProvider(path).WithName("defaults"))
+	if _, err := loader.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := loader.Metadata().Source; got != "defaults" {
+		t.Errorf("Metadata().Source = %q, want %q", got, "defaults")
+	}
+}
+
+func TestMergeValues(t *testing.T) {
+	base := map[string]string{"HOST": "localhost", "PORT": "8080"}
+	override := map[string]string{"PORT": "9090", "DEBUG": "true"}
+
+	merged, err := MergeValues(base, override)
+	if err != nil {
+		t.Fatalf("MergeValues failed: %v", err)
+	}
+	want := map[string]string{"HOST": "localhost", "PORT": "9090", "DEBUG": "true"}
+	if len(merged) != len(want) {
+		t.Errorf("MergeValues() = %v, want %v", merged, want)
+	}
+	for k, v := range want {
+		if merged[k] != v {
+			t.Errorf("merged[%s] = %q, want %q", k, merged[k], v)
+		}
+	}
+	if base["PORT"] != "8080" {
+		t.Error("MergeValues should not modify base")
+	}
+
+	if _, err := MergeValues(base, map[string]string{"__proto__": "x"}); err == nil {
+		t.Error("expected error for dangerous key in override")
+	}
+	if _, err := MergeValues(map[string]string{"constructor": "x"}, nil); err == nil {
+		t.Error("expected error for dangerous key in base")
+	}
+}
+
+func TestFileProvider_WithComments(t *testing.T) {
+	data := `// Service defaults
+{
+	/* the port
+	   the server binds */
+	"SERVER_PORT": 8080, // inline comment
+	"HOMEPAGE": "https://example.com/path", // "//" inside a string is kept
+	"PATTERN": "a/*b*/c",
+	"QUOTED": "say \"hi\" // not a comment",
+	"DB": {
+		// nested comment
+		"HOST": "localhost" /* trailing */
+	}
+}
+// end`
+	path := filepath.Join(t.TempDir(), "config.jsonc")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Strict JSON by default.
+	if _, err := NewFileProvider(path).Fetch(context.Background()); err == nil {
+		t.Fatal("expected parse error for comments without WithComments")
+	}
+
+	values, err := NewFileProvider(path).WithComments().Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	want := map[string]string{
+		"SERVER_PORT": "8080",
+		"HOMEPAGE":    "https://example.com/path",
+		"PATTERN":     "a/*b*/c",
+		"QUOTED":      `say "hi" // not a comment`,
+		"DB_HOST":     "localhost",
+	}
+	for k, v := range want {
+		if values[k] != v {
+			t.Errorf("%s = %q, want %q", k, values[k], v)
+		}
+	}
+}
+
+func TestFileProvider_WithEnvExpansion(t *testing.T) {
+	t.Setenv("DC_TEST_HOME", "/home/svc")
+	t.Setenv("DC_TEST_USER", "svc")
+
+	data := `{
+		"LOG_DIR": "${DC_TEST_HOME}/logs",
+		"GREETING": "hi $DC_TEST_USER!",
+		"MISSING": "${DC_TEST_UNSET_VAR}/x and $DC_TEST_UNSET_VAR",
+		"PRICE": "$$5 and $$DC_TEST_USER",
+		"LONE": "50$",
+		"UNCLOSED": "${DC_TEST_HOME"
+	}`
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := NewFileProvider(path).Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if raw["LOG_DIR"] != "${DC_TEST_HOME}/logs" {
+		t.Errorf("LOG_DIR = %q, want no expansion by default", raw["LOG_DIR"])
+	}
+
+	values, err := NewFileProvider(path).WithEnvExpansion().Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	want := map[string]string{
+		"LOG_DIR":  "/home/svc/logs",
+		"GREETING": "hi svc!",
+		"MISSING":  "${DC_TEST_UNSET_VAR}/x and $DC_TEST_UNSET_VAR",
+		"PRICE":    "$5 and $DC_TEST_USER",
+		"LONE":     "50$",
+		"UNCLOSED": "${DC_TEST_HOME",
+	}
+	for k, v := range want {
+		if values[k] != v {
+			t.Errorf("%s = %q, want %q", k, values[k], v)
+		}
+	}
+}
+
+func TestFileProvider_FetchHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	calls := 0
+	fp := NewFileProvider("/mnt/slow/config.json")
+	fp.readFile = func(string) ([]byte, error) {
+		calls++
+		<-release
+		return []byte(`{"KEY": "value"}`), nil
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fp.Fetch(cancelled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Fetch error = %v, want context.Canceled", err)
+	}
+	if calls != 0 {
+		t.Errorf("readFile called %d times with a cancelled context, want 0", calls)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := fp.Fetch(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Fetch error = %v, want context.DeadlineExceeded", err)
+	}
+	if _, ok := IsProviderError(err); !ok {
+		t.Errorf("Fetch error = %T, want *ProviderError", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Fetch returned after %v, want it to stop waiting at the deadline", elapsed)
+	}
+}
+
 func TestFlattenJSON(t *testing.T) {
 	tests := []struct {
 		name     string
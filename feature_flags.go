@@ -1,28 +1,69 @@
 package dopplerconfig
 
 import (
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // FeatureFlags provides a simple feature flag interface backed by config values.
 // Flags are expected to be stored in Doppler with a consistent naming convention.
 type FeatureFlags struct {
-	values  map[string]string
-	prefix  string
-	mu      sync.RWMutex
-	cache   map[string]bool
+	values      map[string]string
+	prefix      string
+	mu          sync.RWMutex
+	cache       map[string]bool
+	source      string
+	lastUpdated time.Time
+}
+
+// FeatureFlagsOption configures FeatureFlags.
+type FeatureFlagsOption func(*FeatureFlags)
+
+// WithFlagsSource records where the flag values came from, typically
+// ConfigMetadata.Source (e.g. "doppler" or "file:/etc/app/fallback.json").
+func WithFlagsSource(source string) FeatureFlagsOption {
+	return func(f *FeatureFlags) {
+		f.source = source
+	}
 }
 
 // NewFeatureFlags creates a new feature flags helper.
 // The prefix is prepended to all flag names (e.g., "FEATURE_" -> "FEATURE_RAG_ENABLED").
-func NewFeatureFlags(values map[string]string, prefix string) *FeatureFlags {
-	return &FeatureFlags{
-		values: values,
-		prefix: prefix,
-		cache:  make(map[string]bool),
+func NewFeatureFlags(values map[string]string, prefix string, opts ...FeatureFlagsOption) *FeatureFlags {
+	f := &FeatureFlags{
+		values:      values,
+		prefix:      prefix,
+		cache:       make(map[string]bool),
+		lastUpdated: time.Now(),
+	}
+	for _, opt := range opts {
+		opt(f)
 	}
+	return f
+}
+
+// LastUpdated returns when the flag values were last set by NewFeatureFlags,
+// Update or UpdateFromSource.
+// Thread-safe.
+func (f *FeatureFlags) LastUpdated() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.lastUpdated
+}
+
+// Source returns where the current flag values came from, or "" if unknown.
+// Together with LastUpdated it lets a debug endpoint show e.g. "flags last
+// refreshed 12m ago from file:fallback.json".
+// Thread-safe.
+func (f *FeatureFlags) Source() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.source
 }
 
 // IsEnabled checks if a feature flag is enabled.
@@ -146,6 +187,70 @@ func (f *FeatureFlags) GetStringSlice(name string, defaultVal []string) []string
 	return parts
 }
 
+// GetFlag returns the flag's value parsed as T, or def if the flag doesn't
+// exist or can't be parsed. T may be any type the config loader can decode,
+// including int, int64, float64, bool, string, time.Duration, []string and
+// named types built on them such as `type Tier string`. Empty values yield
+// def for every non-string T.
+// Thread-safe.
+func GetFlag[T any](f *FeatureFlags, name string, def T) T {
+	f.mu.RLock()
+	value, exists := f.values[f.buildKey(name)]
+	f.mu.RUnlock()
+	if !exists {
+		return def
+	}
+
+	var result T
+	v := reflect.ValueOf(&result).Elem()
+	if value == "" && v.Kind() != reflect.String {
+		return def
+	}
+	if err := setFieldValue(v, value); err != nil {
+		return def
+	}
+	return result
+}
+
+// All returns every flag whose key starts with the prefix (matched
+// case-insensitively), keyed by flag name without the prefix and in upper
+// case, with its boolean value as IsEnabled would report it. With no prefix,
+// every key is returned unchanged.
+// Thread-safe.
+func (f *FeatureFlags) All() map[string]bool {
+	raw := f.AllRaw()
+	result := make(map[string]bool, len(raw))
+	for name, value := range raw {
+		result[name] = parseBool(value)
+	}
+	return result
+}
+
+// AllRaw is like All but returns the raw, unparsed values.
+// Thread-safe.
+func (f *FeatureFlags) AllRaw() map[string]string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	result := make(map[string]string)
+	for key, value := range f.values {
+		if f.prefix == "" {
+			result[key] = value
+			continue
+		}
+		if len(key) <= len(f.prefix) || !strings.EqualFold(key[:len(f.prefix)], f.prefix) {
+			continue
+		}
+		name := strings.ToUpper(key[len(f.prefix):])
+		// IsEnabled prefers the exact upper-case key over other casings.
+		if _, seen := result[name]; seen && key != f.buildKey(name) {
+			continue
+		}
+		result[name] = value
+	}
+	return result
+}
+
 // Update replaces the underlying values map.
 // This is used when config is reloaded.
 func (f *FeatureFlags) Update(values map[string]string) {
@@ -153,6 +258,17 @@ func (f *FeatureFlags) Update(values map[string]string) {
 	defer f.mu.Unlock()
 	f.values = values
 	f.cache = make(map[string]bool) // Clear cache
+	f.lastUpdated = time.Now()
+}
+
+// UpdateFromSource is like Update but also records the new values' source.
+func (f *FeatureFlags) UpdateFromSource(values map[string]string, source string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values = values
+	f.cache = make(map[string]bool) // Clear cache
+	f.source = source
+	f.lastUpdated = time.Now()
 }
 
 func (f *FeatureFlags) buildKey(name string) string {
@@ -170,24 +286,18 @@ func (f *FeatureFlags) buildKey(name string) string {
 	return f.prefix + name
 }
 
+// parseBool reports whether a flag value is on. Values parseBoolStrict
+// does not recognize count as off.
 func parseBool(s string) bool {
-	if s == "" {
-		return false
-	}
-
-	s = strings.ToLower(strings.TrimSpace(s))
-	switch s {
-	case "true", "1", "yes", "on", "enabled", "enable":
-		return true
-	default:
-		return false
-	}
+	b, _ := parseBoolStrict(s)
+	return b
 }
 
-// FeatureFlagsFromLoader creates a FeatureFlags instance from a loader's current values.
+// FeatureFlagsFromValues creates a FeatureFlags instance from a loader's current values.
 // This is a convenience function for extracting feature flags from a loaded config.
-func FeatureFlagsFromValues(values map[string]string) *FeatureFlags {
-	return NewFeatureFlags(values, "FEATURE_")
+// Pass WithFlagsSource(meta.Source) to record where the values came from.
+func FeatureFlagsFromValues(values map[string]string, opts ...FeatureFlagsOption) *FeatureFlags {
+	return NewFeatureFlags(values, "FEATURE_", opts...)
 }
 
 // CommonFeatureFlags defines common feature flag patterns.
@@ -207,6 +317,11 @@ type CommonFeatureFlags struct {
 }
 
 // RolloutConfig supports percentage-based feature rollouts.
+//
+// A RolloutConfig has no internal locking: treat it as immutable once it is
+// shared between goroutines. To change a rollout while requests evaluate it
+// (e.g. on a Watcher reload), build a new RolloutConfig and swap it in with
+// AtomicRollout.
 type RolloutConfig struct {
 	// Percentage of users/requests that should see the feature (0-100).
 	Percentage int `doppler:"ROLLOUT_PERCENTAGE" default:"0"`
@@ -216,25 +331,49 @@ type RolloutConfig struct {
 
 	// BlockedUsers is a comma-separated list of user IDs that never get the feature.
 	BlockedUsers []string `doppler:"ROLLOUT_BLOCKED_USERS"`
+
+	// BlockTakesPrecedence checks BlockedUsers before AllowedUsers, so a
+	// user in both lists never gets the feature (kill-switch semantics).
+	// Default false: the allow list wins.
+	BlockTakesPrecedence bool `doppler:"ROLLOUT_BLOCK_TAKES_PRECEDENCE" default:"false"`
+
+	// featureName salts the percentage bucketing; see SetFeatureName.
+	featureName string
+}
+
+// SetFeatureName salts percentage bucketing with the feature name, so two
+// features at the same percentage select independent user cohorts while
+// each stays sticky per user. Without a name users are bucketed by
+// hashFunc(userID) alone, as before. Call it before the config is shared.
+func (r *RolloutConfig) SetFeatureName(name string) {
+	r.featureName = name
 }
 
 // ShouldEnable checks if a feature should be enabled for a given user.
 // If userID is in AllowedUsers, returns true.
 // If userID is in BlockedUsers, returns false.
 // Otherwise, uses the percentage-based rollout.
+//
+// A user in both lists is enabled unless BlockTakesPrecedence is set.
 func (r *RolloutConfig) ShouldEnable(userID string, hashFunc func(string) uint32) bool {
-	// Check allow list
-	for _, u := range r.AllowedUsers {
-		if u == userID {
-			return true
-		}
-	}
+	return r.ShouldEnableFor(r.featureName, userID, hashFunc)
+}
 
-	// Check block list
-	for _, u := range r.BlockedUsers {
-		if u == userID {
-			return false
-		}
+// ShouldEnableFor is ShouldEnable with the bucketing salted by feature
+// instead of the name set with SetFeatureName: the percentage check hashes
+// feature+":"+userID. An empty feature hashes userID alone.
+func (r *RolloutConfig) ShouldEnableFor(feature, userID string, hashFunc func(string) uint32) bool {
+	allowed := slices.Contains(r.AllowedUsers, userID)
+	blocked := slices.Contains(r.BlockedUsers, userID)
+
+	if r.BlockTakesPrecedence && blocked {
+		return false
+	}
+	if allowed {
+		return true
+	}
+	if blocked {
+		return false
 	}
 
 	// Use hash for consistent rollout
@@ -245,6 +384,48 @@ func (r *RolloutConfig) ShouldEnable(userID string, hashFunc func(string) uint32
 		return true
 	}
 
-	hash := hashFunc(userID)
+	key := userID
+	if feature != "" {
+		key = feature + ":" + userID
+	}
+	hash := hashFunc(key)
 	return (hash % 100) < uint32(r.Percentage)
 }
+
+// AtomicRollout holds a RolloutConfig that can be replaced while other
+// goroutines call ShouldEnable. Each evaluation sees either the old or the
+// new config in full, never a mix. The zero value disables the feature
+// until Update is called.
+type AtomicRollout struct {
+	cfg atomic.Pointer[RolloutConfig]
+}
+
+// NewAtomicRollout creates an AtomicRollout holding cfg.
+func NewAtomicRollout(cfg *RolloutConfig) *AtomicRollout {
+	a := &AtomicRollout{}
+	a.cfg.Store(cfg)
+	return a
+}
+
+// Update replaces the rollout config. cfg must not be modified afterwards.
+// Thread-safe.
+func (a *AtomicRollout) Update(cfg *RolloutConfig) {
+	a.cfg.Store(cfg)
+}
+
+// Load returns the current rollout config, or nil if none was set.
+// Thread-safe.
+func (a *AtomicRollout) Load() *RolloutConfig {
+	return a.cfg.Load()
+}
+
+// ShouldEnable evaluates the current rollout config for userID; see
+// RolloutConfig.ShouldEnable. It returns false if no config was set.
+// Thread-safe.
+func (a *AtomicRollout) ShouldEnable(userID string, hashFunc func(string) uint32) bool {
+	cfg := a.cfg.Load()
+	if cfg == nil {
+		return false
+	}
+	return cfg.ShouldEnable(userID, hashFunc)
+}
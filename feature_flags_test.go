@@ -1,8 +1,12 @@
 package dopplerconfig
 
 import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewFeatureFlags(t *testing.T) {
@@ -231,6 +235,93 @@ func TestFeatureFlags_GetStringSlice(t *testing.T) {
 	}
 }
 
+type flagTier string
+
+func TestGetFlag(t *testing.T) {
+	ff := NewFeatureFlags(map[string]string{
+		"FEATURE_TIMEOUT":     "250ms",
+		"FEATURE_BAD_TIMEOUT": "soon",
+		"FEATURE_TIER":        "gold",
+		"FEATURE_LIMIT":       "9000000000",
+		"FEATURE_RATIO":       "0.25",
+		"FEATURE_BETA":        "on",
+		"FEATURE_REGIONS":     "us, eu",
+		"FEATURE_EMPTY":       "",
+	}, "FEATURE_")
+
+	if got := GetFlag(ff, "TIMEOUT", time.Second); got != 250*time.Millisecond {
+		t.Errorf("GetFlag(TIMEOUT) = %v, want 250ms", got)
+	}
+	if got := GetFlag(ff, "BAD_TIMEOUT", time.Second); got != time.Second {
+		t.Errorf("GetFlag(BAD_TIMEOUT) = %v, want default 1s", got)
+	}
+	if got := GetFlag(ff, "MISSING", 3*time.Second); got != 3*time.Second {
+		t.Errorf("GetFlag(MISSING) = %v, want default 3s", got)
+	}
+	if got := GetFlag(ff, "TIER", flagTier("free")); got != "gold" {
+		t.Errorf("GetFlag(TIER) = %q, want %q", got, "gold")
+	}
+	if got := GetFlag(ff, "MISSING", flagTier("free")); got != "free" {
+		t.Errorf("GetFlag(MISSING) = %q, want default %q", got, "free")
+	}
+	if got := GetFlag(ff, "LIMIT", int64(0)); got != 9000000000 {
+		t.Errorf("GetFlag(LIMIT) = %d, want 9000000000", got)
+	}
+	if got := GetFlag(ff, "RATIO", 1.0); got != 0.25 {
+		t.Errorf("GetFlag(RATIO) = %v, want 0.25", got)
+	}
+	if got := GetFlag(ff, "BETA", false); !got {
+		t.Error("GetFlag(BETA) = false, want true")
+	}
+	if got := GetFlag(ff, "TIER", true); !got {
+		t.Error("GetFlag(TIER) as bool should fall back to default true")
+	}
+	if got := GetFlag(ff, "REGIONS", []string(nil)); len(got) != 2 || got[0] != "us" || got[1] != "eu" {
+		t.Errorf("GetFlag(REGIONS) = %v, want [us eu]", got)
+	}
+	if got := GetFlag(ff, "EMPTY", 7); got != 7 {
+		t.Errorf("GetFlag(EMPTY) = %d, want default 7", got)
+	}
+}
+
+func TestFeatureFlags_All(t *testing.T) {
+	ff := NewFeatureFlags(map[string]string{
+		"FEATURE_DARK_MODE":  "true",
+		"FEATURE_BETA":       "off",
+		"feature_new_search": "yes",
+		"FEATURE_MAX_ITEMS":  "50",
+		"DATABASE_URL":       "postgres://localhost",
+		"FEATURE_":           "true",
+	}, "FEATURE_")
+
+	all := ff.All()
+	want := map[string]bool{
+		"DARK_MODE":  true,
+		"BETA":       false,
+		"NEW_SEARCH": true,
+		"MAX_ITEMS":  false,
+	}
+	if len(all) != len(want) {
+		t.Errorf("All() = %v, want %v", all, want)
+	}
+	for name, enabled := range want {
+		if got, ok := all[name]; !ok || got != enabled {
+			t.Errorf("All()[%q] = %v, %v; want %v", name, got, ok, enabled)
+		}
+		if got := ff.IsEnabled(name); got != enabled {
+			t.Errorf("IsEnabled(%q) = %v, disagrees with All()", name, got)
+		}
+	}
+	if _, ok := all["DATABASE_URL"]; ok {
+		t.Error("All() should exclude keys without the prefix")
+	}
+
+	raw := ff.AllRaw()
+	if raw["MAX_ITEMS"] != "50" || raw["NEW_SEARCH"] != "yes" {
+		t.Errorf("AllRaw() = %v, want raw values", raw)
+	}
+}
+
 func TestFeatureFlags_Update(t *testing.T) {
 	values := map[string]string{"FEATURE_X": "true"}
 	ff := NewFeatureFlags(values, "FEATURE_")
@@ -249,6 +340,38 @@ func TestFeatureFlags_Update(t *testing.T) {
 	}
 }
 
+func TestFeatureFlags_LastUpdatedAndSource(t *testing.T) {
+	before := time.Now()
+	ff := FeatureFlagsFromValues(map[string]string{"FEATURE_X": "true"}, WithFlagsSource("doppler"))
+
+	first := ff.LastUpdated()
+	if first.Before(before) {
+		t.Errorf("LastUpdated = %v, want set at construction", first)
+	}
+	if ff.Source() != "doppler" {
+		t.Errorf("Source = %q, want %q", ff.Source(), "doppler")
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	ff.Update(map[string]string{"FEATURE_X": "false"})
+	second := ff.LastUpdated()
+	if !second.After(first) {
+		t.Errorf("LastUpdated = %v after Update, want later than %v", second, first)
+	}
+	if ff.Source() != "doppler" {
+		t.Errorf("Source = %q, want unchanged by Update", ff.Source())
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	ff.UpdateFromSource(map[string]string{"FEATURE_X": "true"}, "file:fallback.json")
+	if !ff.LastUpdated().After(second) {
+		t.Error("UpdateFromSource should bump LastUpdated")
+	}
+	if ff.Source() != "file:fallback.json" || !ff.IsEnabled("X") {
+		t.Errorf("Source = %q, IsEnabled = %v; want fallback values", ff.Source(), ff.IsEnabled("X"))
+	}
+}
+
 func TestFeatureFlags_BuildKey(t *testing.T) {
 	ff := NewFeatureFlags(nil, "FEATURE_")
 
@@ -297,6 +420,37 @@ func TestParseBool(t *testing.T) {
 	}
 }
 
+func TestParseBool_LoaderAndFlagsAgree(t *testing.T) {
+	tests := []struct {
+		in    string
+		value bool
+		ok    bool
+	}{
+		{"true", true, true}, {"TRUE", true, true}, {"t", true, true}, {"1", true, true},
+		{"yes", true, true}, {"Y", true, true}, {"on", true, true}, {" enabled ", true, true}, {"enable", true, true},
+		{"false", false, true}, {"F", false, true}, {"0", false, true}, {"no", false, true},
+		{"n", false, true}, {"Off", false, true}, {"disabled", false, true}, {"disable", false, true},
+		{"", false, false}, {"maybe", false, false}, {"2", false, false},
+	}
+	for _, tt := range tests {
+		value, ok := parseBoolStrict(tt.in)
+		if value != tt.value || ok != tt.ok {
+			t.Errorf("parseBoolStrict(%q) = %v, %v; want %v, %v", tt.in, value, ok, tt.value, tt.ok)
+		}
+
+		var field bool
+		err := setFieldValue(reflect.ValueOf(&field).Elem(), tt.in)
+		if (err == nil) != tt.ok || field != tt.value {
+			t.Errorf("config field from %q = %v (err %v); want %v, accepted %v", tt.in, field, err, tt.value, tt.ok)
+		}
+
+		flags := NewFeatureFlags(map[string]string{"FEATURE_X": tt.in}, "FEATURE_")
+		if got := flags.IsEnabled("X"); got != tt.value {
+			t.Errorf("IsEnabled with %q = %v, want %v", tt.in, got, tt.value)
+		}
+	}
+}
+
 func TestFeatureFlagsFromValues(t *testing.T) {
 	values := map[string]string{"FEATURE_X": "true"}
 	ff := FeatureFlagsFromValues(values)
@@ -361,6 +515,18 @@ func TestRolloutConfig_ShouldEnable(t *testing.T) {
 			userID:   "both",
 			expected: true, // AllowedUsers is checked first
 		},
+		{
+			name:     "block precedence: blocked wins over allowed",
+			config:   RolloutConfig{Percentage: 100, AllowedUsers: []string{"both"}, BlockedUsers: []string{"both"}, BlockTakesPrecedence: true},
+			userID:   "both",
+			expected: false,
+		},
+		{
+			name:     "block precedence: allowed-only user still enabled",
+			config:   RolloutConfig{Percentage: 0, AllowedUsers: []string{"admin"}, BlockedUsers: []string{"banned"}, BlockTakesPrecedence: true},
+			userID:   "admin",
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -394,3 +560,86 @@ func TestFeatureFlags_ConcurrentAccess(t *testing.T) {
 	wg.Wait()
 	// No race condition = pass
 }
+
+func TestAtomicRollout_ConcurrentUpdate(t *testing.T) {
+	hash := func(s string) uint32 { return uint32(len(s)) }
+
+	var zero AtomicRollout
+	if zero.ShouldEnable("user", hash) {
+		t.Error("zero AtomicRollout should disable the feature")
+	}
+
+	rollout := NewAtomicRollout(&RolloutConfig{Percentage: 0, AllowedUsers: []string{"admin"}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				if !rollout.ShouldEnable("admin", hash) {
+					t.Error("admin is allowed by every config version")
+					return
+				}
+				rollout.ShouldEnable("user", hash)
+			}
+		}()
+	}
+	for j := 0; j < 500; j++ {
+		rollout.Update(&RolloutConfig{
+			Percentage:   j % 101,
+			AllowedUsers: []string{"admin"},
+			BlockedUsers: []string{"user"},
+		})
+	}
+	wg.Wait()
+
+	rollout.Update(&RolloutConfig{Percentage: 100})
+	if !rollout.ShouldEnable("user", hash) || rollout.Load().Percentage != 100 {
+		t.Error("ShouldEnable should see the latest config after Update")
+	}
+}
+
+func TestRolloutConfig_FeatureNameSalt(t *testing.T) {
+	hash := func(s string) uint32 {
+		h := fnv.New32a()
+		h.Write([]byte(s))
+		return h.Sum32()
+	}
+
+	darkMode := &RolloutConfig{Percentage: 25}
+	darkMode.SetFeatureName("dark-mode")
+	newSearch := &RolloutConfig{Percentage: 25}
+	newSearch.SetFeatureName("new-search")
+
+	var a, b, overlap int
+	for i := 0; i < 1000; i++ {
+		user := fmt.Sprintf("user-%d", i)
+		inA := darkMode.ShouldEnable(user, hash)
+		inB := newSearch.ShouldEnable(user, hash)
+		if inA != darkMode.ShouldEnable(user, hash) {
+			t.Fatalf("%s: bucketing should be sticky", user)
+		}
+		if inB != newSearch.ShouldEnableFor("new-search", user, hash) {
+			t.Fatalf("%s: ShouldEnableFor should match SetFeatureName", user)
+		}
+		if inA {
+			a++
+		}
+		if inB {
+			b++
+		}
+		if inA && inB {
+			overlap++
+		}
+	}
+
+	if a < 180 || a > 320 || b < 180 || b > 320 {
+		t.Errorf("cohort sizes = %d, %d, want about 250 each", a, b)
+	}
+	// Independent 25% cohorts overlap on about 1/16 of users, identical
+	// ones on all of them.
+	if overlap == a || overlap == b || overlap > 120 {
+		t.Errorf("overlap = %d of %d/%d, want independent cohorts", overlap, a, b)
+	}
+}
@@ -0,0 +1,170 @@
+package dopplerconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ai8future/chassis-go/v10/secval"
+)
+
+// KeyPolicy adjusts which config keys providers accept, on top of secval's
+// built-in rejection of dangerous keys such as "__proto__". The zero value
+// keeps secval's behavior unchanged.
+type KeyPolicy struct {
+	// Allow lists keys that are accepted even though secval would reject
+	// them, e.g. a legitimate "__meta__" key. Matching is case-insensitive.
+	Allow []string
+
+	// Deny lists additional keys to reject. Matching is case-insensitive.
+	Deny []string
+
+	// Pattern, if set, must match every key, e.g.
+	// regexp.MustCompile(`^[A-Za-z0-9_.-]+$`) to reject keys with spaces.
+	Pattern *regexp.Regexp
+}
+
+var (
+	keyPolicyMu sync.RWMutex
+	keyPolicy   KeyPolicy
+)
+
+// SetKeyPolicy sets the KeyPolicy applied by every provider in this package
+// and its subpackages (Doppler, file, directory, environment, systemd
+// credentials, Azure Key Vault, etcd, Redis) and by MergeValues. The policy is global: it affects all loaders in the process,
+// so set it once at startup. Allow and Deny apply to keys at any level of a
+// JSON document; Deny and Pattern are also checked against the final
+// flattened keys.
+// SetKeyPolicy(KeyPolicy{}) restores the default.
+func SetKeyPolicy(policy KeyPolicy) {
+	keyPolicyMu.Lock()
+	defer keyPolicyMu.Unlock()
+	keyPolicy = policy
+}
+
+func currentKeyPolicy() KeyPolicy {
+	keyPolicyMu.RLock()
+	defer keyPolicyMu.RUnlock()
+	return keyPolicy
+}
+
+// check returns an error if key is denied or does not match Pattern.
+func (p KeyPolicy) check(key string) error {
+	if err := p.checkDenied(key); err != nil {
+		return err
+	}
+	if p.Pattern != nil && !p.Pattern.MatchString(key) {
+		return fmt.Errorf("%w: %q does not match key policy pattern %s", secval.ErrDangerousKey, key, p.Pattern)
+	}
+	return nil
+}
+
+func (p KeyPolicy) checkDenied(key string) error {
+	for _, denied := range p.Deny {
+		if strings.EqualFold(key, denied) {
+			return fmt.Errorf("%w: %q is denied by key policy", secval.ErrDangerousKey, key)
+		}
+	}
+	return nil
+}
+
+func (p KeyPolicy) allowed(key string) bool {
+	for _, a := range p.Allow {
+		if strings.EqualFold(key, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateJSON runs secval.ValidateJSON on a raw JSON document under the
+// current KeyPolicy: keys on the Allow list are hidden from secval's
+// dangerous-key check (their values are still checked), and Deny is applied
+// to every object key.
+func validateJSON(data []byte) error {
+	policy := currentKeyPolicy()
+	if len(policy.Allow) == 0 && len(policy.Deny) == 0 {
+		return secval.ValidateJSON(data)
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return secval.ValidateJSON(data)
+	}
+	doc, err := applyKeyPolicy(doc, policy)
+	if err != nil {
+		return err
+	}
+	masked, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode document for validation: %w", err)
+	}
+	return secval.ValidateJSON(masked)
+}
+
+// applyKeyPolicy checks every object key in v against policy.Deny and
+// renames allowed keys to neutral placeholders.
+func applyKeyPolicy(v any, policy KeyPolicy) (any, error) {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		n := 0
+		for k, child := range t {
+			if err := policy.checkDenied(k); err != nil {
+				return nil, err
+			}
+			child, err := applyKeyPolicy(child, policy)
+			if err != nil {
+				return nil, err
+			}
+			if policy.allowed(k) {
+				n++
+				k = fmt.Sprintf("\x00allowed:%d", n)
+			}
+			out[k] = child
+		}
+		return out, nil
+	case []any:
+		for i, child := range t {
+			child, err := applyKeyPolicy(child, policy)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = child
+		}
+	}
+	return v, nil
+}
+
+// checkKeyPolicy applies the current KeyPolicy's Deny list and Pattern to
+// a provider's final keys.
+func checkKeyPolicy(values map[string]string) error {
+	policy := currentKeyPolicy()
+	if len(policy.Deny) == 0 && policy.Pattern == nil {
+		return nil
+	}
+	for key := range values {
+		if err := policy.check(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateKeys applies secval's dangerous-key checks and the current
+// KeyPolicy to values read from a non-JSON source, such as secret names
+// listed from a vault. Provider implementations outside this package, like
+// those in the azurekv, etcdprovider and redisprovider subpackages, call it
+// to apply the same checks as the built-in providers.
+func ValidateKeys(values map[string]string) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to encode keys for validation: %w", err)
+	}
+	if err := validateJSON(data); err != nil {
+		return err
+	}
+	return checkKeyPolicy(values)
+}
@@ -0,0 +1,99 @@
+package dopplerconfig
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/ai8future/chassis-go/v10/secval"
+)
+
+// writeFallback writes a JSON fallback file and returns a FileProvider for it.
+func writeFallback(t *testing.T, data string) *FileProvider {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return NewFileProvider(path)
+}
+
+func TestKeyPolicy_PatternRejectsSpaces(t *testing.T) {
+	t.Cleanup(func() { SetKeyPolicy(KeyPolicy{}) })
+
+	fp := writeFallback(t, `{"DB HOST": "localhost", "PORT": "8080"}`)
+
+	// Default policy accepts the key.
+	if _, err := fp.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch with default policy failed: %v", err)
+	}
+
+	SetKeyPolicy(KeyPolicy{Pattern: regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)})
+	_, err := fp.Fetch(context.Background())
+	if !errors.Is(err, secval.ErrDangerousKey) {
+		t.Errorf("Fetch error = %v, want ErrDangerousKey for a key with a space", err)
+	}
+
+	if _, err := MergeValues(map[string]string{"OK": "1"}, map[string]string{"NOT OK": "2"}); err == nil {
+		t.Error("MergeValues should apply the key policy")
+	}
+}
+
+func TestKeyPolicy_AllowAndDeny(t *testing.T) {
+	t.Cleanup(func() { SetKeyPolicy(KeyPolicy{}) })
+
+	fp := writeFallback(t, `{"constructor": "factory", "NESTED": {"__proto__": "x"}, "LEGACY_KEY": "old"}`)
+
+	if _, err := fp.Fetch(context.Background()); !errors.Is(err, secval.ErrDangerousKey) {
+		t.Fatalf("Fetch error = %v, want ErrDangerousKey by default", err)
+	}
+
+	// Allowing one key does not allow the others.
+	SetKeyPolicy(KeyPolicy{Allow: []string{"constructor"}})
+	if _, err := fp.Fetch(context.Background()); !errors.Is(err, secval.ErrDangerousKey) {
+		t.Fatalf("Fetch error = %v, want __proto__ still rejected", err)
+	}
+
+	SetKeyPolicy(KeyPolicy{Allow: []string{"constructor", "__proto__"}})
+	values, err := fp.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch with allowlist failed: %v", err)
+	}
+	if values["constructor"] != "factory" || values["NESTED___proto__"] != "x" {
+		t.Errorf("values = %v, want allowlisted keys kept", values)
+	}
+
+	SetKeyPolicy(KeyPolicy{Allow: []string{"constructor", "__proto__"}, Deny: []string{"legacy_key"}})
+	if _, err := fp.Fetch(context.Background()); !errors.Is(err, secval.ErrDangerousKey) {
+		t.Errorf("Fetch error = %v, want LEGACY_KEY denied", err)
+	}
+}
+
+func TestKeyPolicy_EnvProvider(t *testing.T) {
+	t.Cleanup(func() { SetKeyPolicy(KeyPolicy{}) })
+	t.Setenv("KPTEST_DB_HOST", "localhost")
+	t.Setenv("KPTEST_legacy", "old")
+	p := NewEnvProvider("KPTEST_")
+	ctx := context.Background()
+
+	if _, err := p.Fetch(ctx); err != nil {
+		t.Fatalf("Fetch with default policy failed: %v", err)
+	}
+
+	SetKeyPolicy(KeyPolicy{Pattern: regexp.MustCompile(`^[A-Z0-9_]+$`)})
+	if _, err := p.Fetch(ctx); !errors.Is(err, secval.ErrDangerousKey) {
+		t.Errorf("Fetch error = %v, want KPTEST_legacy rejected by pattern", err)
+	}
+	values, err := p.FetchKeys(ctx, []string{"KPTEST_DB_HOST"})
+	if err != nil || values["KPTEST_DB_HOST"] != "localhost" {
+		t.Errorf("FetchKeys = %v, %v; want KPTEST_DB_HOST", values, err)
+	}
+
+	SetKeyPolicy(KeyPolicy{Deny: []string{"kptest_db_host"}})
+	if _, err := p.FetchKeys(ctx, []string{"KPTEST_DB_HOST"}); !errors.Is(err, secval.ErrDangerousKey) {
+		t.Errorf("FetchKeys error = %v, want KPTEST_DB_HOST denied", err)
+	}
+}
@@ -1,14 +1,24 @@
 package dopplerconfig
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"os"
 	"reflect"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/ai8future/chassis-go/v10/call"
 )
 
 // Loader provides typed configuration loading with automatic struct mapping.
@@ -17,9 +27,25 @@ type Loader[T any] interface {
 	// Load fetches and parses configuration into the typed struct.
 	Load(ctx context.Context) (*T, error)
 
-	// Reload refreshes the configuration from the source.
+	// Reload refreshes the configuration from the source. Calls that
+	// overlap an in-flight Reload share its fetch and get its result.
+	// Keys that were present in the previous load but are now missing are
+	// logged and listed in Warnings; their fields revert to their defaults.
 	Reload(ctx context.Context) (*T, error)
 
+	// LoadAndValidate loads like Load but reports every problem at once:
+	// values that fail to parse, missing required keys and validate-tag or
+	// Validator failures are returned together as ValidationErrors. If
+	// there is any problem the config is not applied and Current is
+	// unchanged.
+	LoadAndValidate(ctx context.Context) (*T, error)
+
+	// Warmup runs the recommended startup sequence in one call: it loads
+	// and validates like LoadAndValidate, so an invalid config is never
+	// applied, which also primes any FeatureFlags bound with
+	// WithFeatureFlags. It returns the first error encountered.
+	Warmup(ctx context.Context) error
+
 	// Current returns the currently loaded configuration.
 	// Returns nil if Load has not been called.
 	Current() *T
@@ -31,10 +57,66 @@ type Loader[T any] interface {
 	// Metadata returns information about the loaded configuration.
 	Metadata() ConfigMetadata
 
-	// Close releases resources used by the loader.
+	// Warnings returns the non-fatal problems found while unmarshaling the
+	// current config, such as a value that failed to parse; it is a copy
+	// of Metadata().Warnings.
+	Warnings() []string
+
+	// Version returns a counter that increases each time a Load or Reload
+	// applies a config that differs from the current one, and holds
+	// steady on reloads that yield identical content. It is 0 before the
+	// first load and also appears in Metadata().Version, so caches
+	// downstream can be keyed on it.
+	Version() uint64
+
+	// Stale reports whether the most recent Load or Reload failed while an
+	// earlier config remains in use via Current.
+	Stale() bool
+
+	// LastError returns the error from the most recent Load or Reload,
+	// or nil if it succeeded.
+	LastError() error
+
+	// Providers returns the primary and fallback providers the loader was
+	// constructed with, e.g. for a debug handler reporting
+	// "primary: doppler, fallback: file:/etc/app.json". Either is nil if
+	// absent, as is the primary in offline mode.
+	Providers() (primary, fallback Provider)
+
+	// DebugString returns a human-readable multi-line summary of the
+	// loader's state for debug endpoints: source, load time, key count,
+	// the primary's circuit breaker state when it has one, staleness and
+	// the number of warnings. It never includes config values.
+	DebugString() string
+
+	// VerifyKeys reports which of keys are missing from the configured
+	// source, without decoding or replacing the current config. It lists
+	// names via NameFetcher when the provider supports it, so no secret
+	// values are downloaded, and falls back to the fallback provider if
+	// the primary fails. Missing keys are returned in the order given.
+	VerifyKeys(ctx context.Context, keys []string) ([]string, error)
+
+	// Close cancels any in-flight Load or Reload, waits for it to return,
+	// and releases resources used by the loader. Load and Reload called
+	// after Close return ErrLoaderClosed. Close must not be called from an
+	// OnChange callback, since it waits for the reload running the callback.
 	Close() error
 }
 
+// ErrLoaderClosed is returned by Load and Reload after Close.
+var ErrLoaderClosed = errors.New("loader is closed")
+
+// errNoProviders is returned by VerifyKeys when there is nothing to query.
+var errNoProviders = errors.New("loader has no providers")
+
+// ErrRequiredEmpty is wrapped by the error WithRejectEmptyRequired returns
+// for a required field whose key is present with an empty value.
+var ErrRequiredEmpty = errors.New("required value is empty")
+
+// SourceDefaults is the ConfigMetadata.Source reported when every provider
+// failed and the FailurePolicyWarn policy fell back to struct defaults.
+const SourceDefaults = "defaults"
+
 // LoaderOption configures a Loader.
 type LoaderOption[T any] func(*loader[T])
 
@@ -46,17 +128,227 @@ func WithLoaderLogger[T any](logger *slog.Logger) LoaderOption[T] {
 	}
 }
 
+// WithStrictKeys enables strict unknown-key detection. After unmarshaling,
+// every provider key not consumed by a struct field is reported as a warning
+// in ConfigMetadata.Warnings. This catches renamed Doppler keys whose struct
+// tags were not updated. Doppler's auto-injected DOPPLER_* keys and any keys
+// listed in ignore are never reported.
+func WithStrictKeys[T any](ignore ...string) LoaderOption[T] {
+	return func(l *loader[T]) {
+		l.strictKeys = true
+		if l.ignoreKeys == nil {
+			l.ignoreKeys = make(map[string]bool, len(ignore))
+		}
+		for _, key := range ignore {
+			l.ignoreKeys[key] = true
+		}
+	}
+}
+
+// WithDefaults sets programmatic default values, keyed by doppler key. They
+// are used for keys the provider does not supply (or supplies empty), which
+// suits defaults that are computed at runtime rather than fixed in tags.
+//
+// Precedence: provider value > WithDefaults > RegisterDefaultFunc > `default:` struct tag.
+func WithDefaults[T any](defaults map[string]string) LoaderOption[T] {
+	return func(l *loader[T]) {
+		l.defaults = defaults
+	}
+}
+
+// WithLoadRetry retries the whole load (primary provider, then fallback) up
+// to attempts times before the failure policy is applied, waiting delay
+// before the second attempt and doubling it after each failure. Waiting
+// honors ctx cancellation.
+//
+// This is separate from the per-request retries done by DopplerProvider's
+// call.Client: those retry a single HTTP request, while WithLoadRetry caps
+// the number of complete load attempts, which bounds startup time when
+// Doppler is flaky.
+func WithLoadRetry[T any](attempts int, delay time.Duration) LoaderOption[T] {
+	return func(l *loader[T]) {
+		l.loadRetryAttempts = attempts
+		l.loadRetryDelay = delay
+	}
+}
+
+// WithKeyTransform normalizes every provider key with fn before keys are
+// matched against struct tags, e.g. strings.ToUpper to accept lowercase keys
+// or a replacer turning "/" into "_". It runs before WithDefaults and
+// WithEnvOverrides are applied. If two keys transform to the same name, the
+// value whose original key sorts first wins and a warning is logged.
+func WithKeyTransform[T any](fn func(string) string) LoaderOption[T] {
+	return func(l *loader[T]) {
+		l.keyTransform = fn
+	}
+}
+
+// WithValueTransform rewrites every value with fn before it is parsed into
+// its field, e.g. to trim a trailing slash off URLs or upper-case an enum.
+// fn receives the key as matched against struct tags and runs last, after
+// WithDefaults, WithEnvOverrides and WithFlagOverrides are applied; values
+// from default struct tags are not passed through it.
+func WithValueTransform[T any](fn func(key, value string) string) LoaderOption[T] {
+	return func(l *loader[T]) {
+		l.valueTransform = fn
+	}
+}
+
+// WithCaseInsensitiveKeys falls back to a case-insensitive lookup when a
+// struct tag's key is not found exactly, e.g. so a fallback file with
+// "log_level" still fills `doppler:"LOG_LEVEL"`. Exact matches always win.
+// Matching is exact-only by default.
+func WithCaseInsensitiveKeys[T any]() LoaderOption[T] {
+	return func(l *loader[T]) {
+		l.caseInsensitive = true
+	}
+}
+
+// DefaultEnvOverridePrefix is the env var prefix used by WithEnvOverrides
+// when no prefix is given.
+const DefaultEnvOverridePrefix = "DOPPLER_OVERRIDE_"
+
+// WithEnvOverrides layers process environment variables on top of the
+// fetched values. Any env var starting with prefix (DefaultEnvOverridePrefix
+// if empty) overrides the key named by the remainder, e.g.
+// DOPPLER_OVERRIDE_LOG_LEVEL=debug replaces LOG_LEVEL.
+//
+// Unlike using EnvProvider as a fallback, overrides apply on top of a
+// successful Doppler (or fallback) load, which makes them useful for quick
+// local debugging without touching Doppler.
+func WithEnvOverrides[T any](prefix string) LoaderOption[T] {
+	return func(l *loader[T]) {
+		if prefix == "" {
+			prefix = DefaultEnvOverridePrefix
+		}
+		l.envOverridePrefix = prefix
+	}
+}
+
+// WithFlagOverrides overlays values computed by the caller, typically from
+// parsed command-line flags, keyed by doppler key. They win over everything
+// else: provider values, WithEnvOverrides, WithDefaults and default tags.
+// Unlike WithEnvOverrides the values are explicit, so only flags the user
+// actually set should be included. The map is copied.
+func WithFlagOverrides[T any](overrides map[string]string) LoaderOption[T] {
+	return func(l *loader[T]) {
+		l.flagOverrides = maps.Clone(overrides)
+	}
+}
+
+// WithNestedSeparator sets the separator used to build the keys of nested
+// struct fields. A parent's doppler tag is the prefix base, so with "_" a
+// `DB DBConfig doppler:"DATABASE"` field whose child is tagged "HOST" reads
+// DATABASE_HOST; untagged parents use the field name, e.g. DB_Host. By
+// default a tagged parent joins with "_" and an untagged parent with ".".
+func WithNestedSeparator[T any](sep string) LoaderOption[T] {
+	return func(l *loader[T]) {
+		l.nestedSep = sep
+	}
+}
+
+// WithValidateOnLoad makes every Load and Reload validate the config the
+// way LoadAndValidate does. Any parse failure, missing required key or
+// validation error is returned as ValidationErrors and the config is not
+// applied, so a reload bringing bad values keeps the previous good config.
+func WithValidateOnLoad[T any]() LoaderOption[T] {
+	return func(l *loader[T]) {
+		l.validateOnLoad = true
+	}
+}
+
+// WithStrictParse makes Load and Reload fail when a value cannot be parsed
+// into its field (a bad int or duration, invalid JSON, a failed
+// TextUnmarshaler) instead of leaving the field zero with a warning. The
+// error wraps the failures as ValidationErrors with Code "parse", and the
+// config is not applied.
+func WithStrictParse[T any]() LoaderOption[T] {
+	return func(l *loader[T]) {
+		l.strictParse = true
+	}
+}
+
+// WithProfile sets the config name that selects profile tag defaults,
+// overriding BootstrapConfig.Config. Loaders made by NewLoaderWithProvider
+// have no bootstrap config, so this is how they pick a profile.
+func WithProfile[T any](name string) LoaderOption[T] {
+	return func(l *loader[T]) {
+		l.profile = name
+	}
+}
+
+// WithRejectEmptyRequired makes a required field whose key is present but
+// set to "" an error wrapping ErrRequiredEmpty, even when the field has a
+// default. Without it an empty value counts as missing: the default
+// applies if there is one. An explicitly empty required secret is usually
+// a misconfiguration, so this surfaces it instead of masking it.
+func WithRejectEmptyRequired[T any]() LoaderOption[T] {
+	return func(l *loader[T]) {
+		l.rejectEmptyRequired = true
+	}
+}
+
+// WithFeatureFlags binds flags to the loader: every Load or Reload that
+// applies a config also refreshes flags with the loaded values and their
+// source, before OnChange callbacks run.
+func WithFeatureFlags[T any](flags *FeatureFlags) LoaderOption[T] {
+	return func(l *loader[T]) {
+		l.flags = flags
+	}
+}
+
 // loader implements Loader[T].
 type loader[T any] struct {
-	provider Provider
-	fallback Provider
+	provider  Provider
+	fallback  Provider
 	bootstrap BootstrapConfig
 	logger    *slog.Logger
 
+	strictKeys          bool
+	ignoreKeys          map[string]bool
+	envOverridePrefix   string
+	flagOverrides       map[string]string
+	keyTransform        func(string) string
+	valueTransform      func(key, value string) string
+	caseInsensitive     bool
+	nestedSep           string
+	validateOnLoad      bool
+	strictParse         bool
+	rejectEmptyRequired bool
+	profile             string
+	flags               *FeatureFlags
+	defaults            map[string]string
+	loadRetryAttempts   int
+	loadRetryDelay      time.Duration
+
+	// current is read lock-free by Current; it is only stored while
+	// holding mu, so writers stay ordered with the metadata and callbacks.
+	current atomic.Pointer[T]
+
 	mu        sync.RWMutex
-	current   *T
+	version   uint64
 	metadata  ConfigMetadata
 	callbacks []func(old, new *T)
+	lastErr   error
+	stale     bool
+
+	// keys are the provider keys behind the current config, for reporting
+	// keys removed by the next load.
+	keys []string
+
+	// reusable is set while the current config was parsed from the
+	// values of the most recent fetch, so a NotModified result can keep it.
+	reusable bool
+
+	// reloading is the Reload in flight, shared by overlapping calls.
+	reloadMu  sync.Mutex
+	reloading *reloadCall[T]
+
+	// closeCtx is cancelled by Close; every load derives its context from it.
+	closeCtx    context.Context
+	closeCancel context.CancelFunc
+	closed      bool
+	inflight    sync.WaitGroup
 }
 
 // NewLoader creates a new typed configuration loader.
@@ -66,12 +358,17 @@ func NewLoader[T any](bootstrap BootstrapConfig, opts ...LoaderOption[T]) (Loade
 		bootstrap: bootstrap,
 		logger:    slog.Default(),
 	}
+	l.closeCtx, l.closeCancel = context.WithCancel(context.Background())
 
 	for _, opt := range opts {
 		opt(l)
 	}
 
-	// Initialize primary provider (Doppler)
+	if bootstrap.Offline && !bootstrap.HasFallback() {
+		return nil, fmt.Errorf("offline mode requires a fallback: set DOPPLER_FALLBACK_PATH")
+	}
+
+	// Initialize primary provider (Doppler), skipped in offline mode
 	if bootstrap.IsEnabled() {
 		provider, err := NewDopplerProvider(bootstrap.Token, bootstrap.Project, bootstrap.Config,
 			WithProviderLogger(l.logger),
@@ -103,6 +400,7 @@ func NewLoaderWithProvider[T any](provider Provider, fallback Provider, opts ...
 		fallback: fallback,
 		logger:   slog.Default(),
 	}
+	l.closeCtx, l.closeCancel = context.WithCancel(context.Background())
 	for _, opt := range opts {
 		opt(l)
 	}
@@ -111,40 +409,121 @@ func NewLoaderWithProvider[T any](provider Provider, fallback Provider, opts ...
 
 // Load implements Loader.Load.
 func (l *loader[T]) Load(ctx context.Context) (*T, error) {
-	return l.loadFromProvider(ctx, false)
+	return l.loadFromProvider(ctx, false, l.validateOnLoad, nil)
 }
 
-// Reload implements Loader.Reload.
+// Reload implements Loader.Reload. Concurrent calls share one reload. A
+// caller that joins another's reload returns ctx.Err() if its ctx ends
+// first; otherwise it gets the same config and error as the caller that
+// started the reload, unless that caller's ctx ended before the reload
+// finished, in which case it starts or joins a fresh reload while its own
+// ctx and the loader are live.
 func (l *loader[T]) Reload(ctx context.Context) (*T, error) {
-	return l.loadFromProvider(ctx, true)
+	for {
+		l.reloadMu.Lock()
+		c := l.reloading
+		if c == nil {
+			break // start the reload, still holding reloadMu
+		}
+		c.waiters++
+		l.reloadMu.Unlock()
+
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if c.leaderDone && c.err != nil && ctx.Err() == nil && l.closeCtx.Err() == nil {
+			continue
+		}
+		return c.cfg, c.err
+	}
+	c := &reloadCall[T]{done: make(chan struct{})}
+	l.reloading = c
+	l.reloadMu.Unlock()
+
+	c.cfg, c.err = l.loadFromProvider(ctx, true, l.validateOnLoad, nil)
+	c.leaderDone = ctx.Err() != nil
+
+	l.reloadMu.Lock()
+	l.reloading = nil
+	l.reloadMu.Unlock()
+	close(c.done)
+	return c.cfg, c.err
 }
 
-func (l *loader[T]) loadFromProvider(ctx context.Context, isReload bool) (*T, error) {
-	var values map[string]string
-	var source string
-	var err error
+// reloadCall is a Reload in progress; done is closed once cfg and err are
+// set.
+type reloadCall[T any] struct {
+	done chan struct{}
+	cfg  *T
+	err  error
 
-	// Try primary provider first
-	if l.provider != nil {
-		values, err = l.provider.Fetch(ctx)
-		if err == nil {
-			source = l.provider.Name()
-		}
+	// leaderDone records that the ctx of the caller running the reload
+	// ended before it finished, so err is that caller's, not the others'.
+	leaderDone bool
+
+	// waiters counts the callers that joined the reload after it started;
+	// guarded by loader.reloadMu.
+	waiters int
+}
+
+// checkedReloader is implemented by loaders that can run a check on a
+// freshly parsed config before it replaces the current one. The Watcher
+// uses it for WithValidateReload.
+type checkedReloader[T any] interface {
+	reloadChecked(ctx context.Context, check func(*T) error) (*T, error)
+}
+
+// reloadChecked reloads like Reload, but a non-nil error from check rejects
+// the new config before it is applied or any OnChange callback runs.
+func (l *loader[T]) reloadChecked(ctx context.Context, check func(*T) error) (*T, error) {
+	return l.loadFromProvider(ctx, true, l.validateOnLoad, check)
+}
+
+// LoadAndValidate implements Loader.LoadAndValidate.
+func (l *loader[T]) LoadAndValidate(ctx context.Context) (*T, error) {
+	return l.loadFromProvider(ctx, false, true, nil)
+}
+
+// Warmup implements Loader.Warmup.
+func (l *loader[T]) Warmup(ctx context.Context) error {
+	_, err := l.LoadAndValidate(ctx)
+	return err
+}
+
+func (l *loader[T]) loadFromProvider(ctx context.Context, isReload, validate bool, check func(*T) error) (*T, error) {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil, ErrLoaderClosed
 	}
+	l.inflight.Add(1)
+	l.mu.Unlock()
+	defer l.inflight.Done()
 
-	// Fall back if primary failed or wasn't available
-	if values == nil && l.fallback != nil {
-		if err != nil {
-			l.logger.Warn("primary provider failed, trying fallback",
-				"error", err,
-				"fallback", l.fallback.Name(),
-			)
-		}
-		values, err = l.fallback.Fetch(ctx)
-		if err == nil {
-			source = l.fallback.Name()
-		}
+	ctx, cancel := mergeCancel(ctx, l.closeCtx)
+	defer cancel()
+
+	cfg, err := l.fetchAndApply(ctx, isReload, validate, check)
+	if err != nil {
+		l.mu.Lock()
+		l.lastErr = err
+		l.stale = l.current.Load() != nil
+		l.reusable = false
+		l.mu.Unlock()
 	}
+	return cfg, err
+}
+
+// fetchAndApply fetches values, parses them into a new config and, on
+// success, swaps it in as the current config. With validate set, parse
+// failures and validation errors are collected into ValidationErrors and
+// the config is not applied if there are any. A non-nil check runs last and
+// rejects the config in the same way.
+func (l *loader[T]) fetchAndApply(ctx context.Context, isReload, validate bool, check func(*T) error) (*T, error) {
+	result, source, role, err := l.fetchWithRetry(ctx)
+	values := result.Values
 
 	// Handle failure based on policy
 	if values == nil {
@@ -154,7 +533,7 @@ func (l *loader[T]) loadFromProvider(ctx context.Context, isReload bool) (*T, er
 		case FailurePolicyWarn:
 			l.logger.Warn("all providers failed, using defaults only", "error", err)
 			values = make(map[string]string)
-			source = "defaults"
+			source, role = SourceDefaults, ""
 		default:
 			if err != nil {
 				return nil, fmt.Errorf("failed to load configuration: %w", err)
@@ -163,28 +542,104 @@ func (l *loader[T]) loadFromProvider(ctx context.Context, isReload bool) (*T, er
 		}
 	}
 
+	stats, _ := l.primaryStats(role)
+	if isReload && result.NotModified {
+		if cfg, ok := l.reuseCurrent(source, role, stats); ok {
+			return cfg, nil
+		}
+	}
+
+	if l.keyTransform != nil {
+		values = l.transformKeys(values)
+	}
+	if len(l.defaults) > 0 {
+		values = applyDefaults(values, l.defaults)
+	}
+	if l.envOverridePrefix != "" {
+		values = l.overlayEnv(values)
+	}
+	if len(l.flagOverrides) > 0 {
+		values = l.overlayFlags(values)
+	}
+	if l.valueTransform != nil {
+		values = l.transformValues(values)
+	}
+
 	// Parse values into struct
 	cfg := new(T)
-	warnings, parseErr := unmarshalConfig(values, cfg)
-	if parseErr != nil {
+	d := newDecoder(values)
+	if l.caseInsensitive {
+		d.enableCaseInsensitive()
+	}
+	d.nestedSep = l.nestedSep
+	d.rejectEmptyRequired = l.rejectEmptyRequired
+	d.profile = cmp.Or(l.profile, l.bootstrap.Config)
+	// When validating, missing required fields are reported by Validate
+	// together with everything else instead of aborting the decode.
+	d.skipRequired = validate
+	if l.logger.Enabled(ctx, slog.LevelDebug) {
+		d.logger = l.logger
+	}
+	if parseErr := d.decode(cfg); parseErr != nil {
 		return nil, fmt.Errorf("failed to parse configuration: %w", parseErr)
 	}
+	if validate {
+		if errs := collectValidationErrors(cfg, d.parseErrors); len(errs) > 0 {
+			return nil, errs
+		}
+	}
+	if l.strictParse && len(d.parseErrors) > 0 {
+		return nil, fmt.Errorf("failed to parse configuration: %w", d.parseErrors)
+	}
+	if check != nil {
+		if err := check(cfg); err != nil {
+			return nil, fmt.Errorf("configuration rejected: %w", err)
+		}
+	}
+	warnings := d.warnings
+	if l.strictKeys {
+		for _, key := range d.unusedKeys(l.ignoreKeys) {
+			warnings = append(warnings, fmt.Sprintf("unused key %s: not mapped to any config field", key))
+		}
+	}
 
 	// Update state
 	l.mu.Lock()
-	old := l.current
-	l.current = cfg
+	removed := removedKeys(l.keys, values)
+	for _, key := range removed {
+		warnings = append(warnings, fmt.Sprintf("key %s removed since the previous load; its field reverts to its default", key))
+	}
+	l.keys = slices.Collect(maps.Keys(values))
+	old := l.current.Load()
+	if old == nil || !reflect.DeepEqual(old, cfg) {
+		l.version++
+	}
+	l.current.Store(cfg)
 	l.metadata = ConfigMetadata{
-		Source:   source,
-		LoadedAt: time.Now(),
-		Project:  l.bootstrap.Project,
-		Config:   l.bootstrap.Config,
-		KeyCount: len(values),
-		Warnings: warnings,
+		Version:         l.version,
+		Source:          source,
+		Role:            role,
+		LoadedAt:        time.Now(),
+		Project:         l.bootstrap.Project,
+		Config:          l.bootstrap.Config,
+		ETag:            stats.ETag,
+		EffectiveConfig: cmp.Or(stats.EffectiveConfig, l.bootstrap.Config),
+		KeyCount:        len(values),
+		Warnings:        warnings,
 	}
+	l.lastErr = nil
+	l.stale = false
+	l.reusable = true
 	callbacks := l.callbacks
 	l.mu.Unlock()
 
+	if len(removed) > 0 {
+		l.logger.Warn("config keys removed since previous load", "keys", removed, "source", source)
+	}
+	if l.flags != nil {
+		l.flags.UpdateFromSource(values, source)
+	}
+
 	// Notify callbacks if this is a reload
 	if isReload && old != nil {
 		for _, cb := range callbacks {
@@ -195,11 +650,259 @@ func (l *loader[T]) loadFromProvider(ctx context.Context, isReload bool) (*T, er
 	return cfg, nil
 }
 
-// Current implements Loader.Current.
+// removedKeys returns, sorted, the keys in previous that values lacks.
+func removedKeys(previous []string, values map[string]string) []string {
+	var removed []string
+	for _, key := range previous {
+		if _, ok := values[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// applyDefaults returns a copy of values with defaults filled in for keys
+// that are missing or empty.
+func applyDefaults(values, defaults map[string]string) map[string]string {
+	result := make(map[string]string, len(values)+len(defaults))
+	for k, v := range defaults {
+		result[k] = v
+	}
+	for k, v := range values {
+		if v != "" || result[k] == "" {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// overlayEnv returns a copy of values with matching env overrides applied.
+// The input map is never mutated since providers may share it with a cache.
+func (l *loader[T]) overlayEnv(values map[string]string) map[string]string {
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		result[k] = v
+	}
+
+	for _, env := range os.Environ() {
+		key, value := splitEnv(env)
+		if !hasPrefix(key, l.envOverridePrefix) || len(key) == len(l.envOverridePrefix) {
+			continue
+		}
+		key = key[len(l.envOverridePrefix):]
+		result[key] = value
+		l.logger.Info("config value overridden from environment", "key", key)
+	}
+
+	return result
+}
+
+// overlayFlags returns a copy of values with the WithFlagOverrides values
+// applied.
+func (l *loader[T]) overlayFlags(values map[string]string) map[string]string {
+	result := maps.Clone(values)
+	for key, value := range l.flagOverrides {
+		result[key] = value
+		l.logger.Info("config value overridden from flag", "key", key)
+	}
+	return result
+}
+
+// transformValues returns a copy of values with valueTransform applied to
+// each value.
+func (l *loader[T]) transformValues(values map[string]string) map[string]string {
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		result[k] = l.valueTransform(k, v)
+	}
+	return result
+}
+
+// transformKeys returns a copy of values with every key passed through
+// keyTransform.
+func (l *loader[T]) transformKeys(values map[string]string) map[string]string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]string, len(values))
+	origin := make(map[string]string, len(values))
+	for _, k := range keys {
+		nk := l.keyTransform(k)
+		if prev, dup := origin[nk]; dup {
+			l.logger.Warn("key transform collision, keeping first key", "key", nk, "kept", prev, "dropped", k)
+			continue
+		}
+		origin[nk] = k
+		result[nk] = values[k]
+	}
+	return result
+}
+
+// fetchWithRetry runs fetchValues up to loadRetryAttempts times with
+// exponential backoff between attempts, stopping early if ctx is done.
+func (l *loader[T]) fetchWithRetry(ctx context.Context) (FetchResult, string, ProviderRole, error) {
+	attempts := l.loadRetryAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var result FetchResult
+	var source string
+	var role ProviderRole
+	var err error
+	delay := l.loadRetryDelay
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		result, source, role, err = l.fetchValues(ctx)
+		if result.Values != nil || attempt == attempts {
+			break
+		}
+
+		l.logger.Warn("configuration load failed, retrying",
+			"error", err,
+			"attempt", attempt,
+			"max_attempts", attempts,
+			"delay", delay,
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return FetchResult{}, "", "", fmt.Errorf("load retry aborted after %d attempts: %w (last error: %v)", attempt, ctx.Err(), err)
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+
+	return result, source, role, err
+}
+
+// fetchStatter is implemented by providers that report diagnostics about
+// their last fetch, such as DopplerProvider.
+type fetchStatter interface {
+	LastFetch() FetchStats
+}
+
+// primaryStats returns the primary provider's last fetch stats if it served
+// the values (role is RolePrimary) and reports them.
+func (l *loader[T]) primaryStats(role ProviderRole) (FetchStats, bool) {
+	if l.provider == nil || role != RolePrimary {
+		return FetchStats{}, false
+	}
+	fs, ok := l.provider.(fetchStatter)
+	if !ok {
+		return FetchStats{}, false
+	}
+	return fs.LastFetch(), true
+}
+
+// reuseCurrent keeps the current config after source reported its values
+// as not modified, provided the current config was parsed from source's
+// previous fetch in the same role (and, for providers reporting FetchStats, the same ETag).
+// Only LoadedAt is refreshed, so a reload of an unchanged large config
+// neither re-parses nor allocates, and OnChange callbacks do not run.
+// Environment overrides are re-read on every reload, so loaders with
+// WithEnvOverrides always re-parse.
+func (l *loader[T]) reuseCurrent(source string, role ProviderRole, stats FetchStats) (*T, bool) {
+	if l.envOverridePrefix != "" {
+		return nil, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.current.Load() == nil || !l.reusable || l.metadata.Source != source || l.metadata.Role != role || l.metadata.ETag != stats.ETag {
+		return nil, false
+	}
+	l.metadata.LoadedAt = time.Now()
+	l.lastErr = nil
+	l.stale = false
+	return l.current.Load(), true
+}
+
+// cacheInvalidator is implemented by providers that cache fetch results,
+// such as DopplerProvider and CachingProvider.
+type cacheInvalidator interface {
+	InvalidateCache()
+}
+
+// invalidateProviderCaches drops the caches of every provider given.
+func invalidateProviderCaches(providers ...Provider) {
+	for _, p := range providers {
+		if ci, ok := p.(cacheInvalidator); ok {
+			ci.InvalidateCache()
+		}
+	}
+}
+
+// invalidateCaches drops the provider caches, so the next Reload reads
+// fresh values. WebhookHandler calls it before reloading.
+func (l *loader[T]) invalidateCaches() {
+	invalidateProviderCaches(l.provider, l.fallback)
+}
+
+// circuitStater is implemented by providers with a circuit breaker, such as
+// DopplerProvider.
+type circuitStater interface {
+	CircuitState() call.State
+}
+
+// fetchValues tries the primary provider, then the fallback, and returns
+// the values with the name and role of the provider that served them.
+// It returns nil values if neither produced any.
+func (l *loader[T]) fetchValues(ctx context.Context) (FetchResult, string, ProviderRole, error) {
+	var result FetchResult
+	var source string
+	var role ProviderRole
+	var err error
+
+	// Try primary provider first, unless its circuit breaker is open and
+	// there is a fallback to go to instead
+	if l.provider != nil {
+		if cs, ok := l.provider.(circuitStater); ok && l.fallback != nil && cs.CircuitState() == call.StateOpen {
+			err = fmt.Errorf("%s: %w", l.provider.Name(), call.ErrCircuitOpen)
+		} else {
+			result, err = fetchResult(ctx, l.provider)
+			if err == nil {
+				source, role = l.provider.Name(), RolePrimary
+			}
+		}
+	}
+
+	// Fall back if primary failed or wasn't available
+	if result.Values == nil && l.fallback != nil {
+		if err != nil {
+			l.logger.Warn("primary provider failed, trying fallback",
+				"error", err,
+				"fallback", l.fallback.Name(),
+			)
+		}
+		result, err = fetchResult(ctx, l.fallback)
+		if err == nil {
+			source, role = l.fallback.Name(), RoleFallback
+		}
+	}
+
+	return result, source, role, err
+}
+
+// fetchResult fetches from p, through FetchResult if p implements
+// ResultFetcher.
+func fetchResult(ctx context.Context, p Provider) (FetchResult, error) {
+	if rf, ok := p.(ResultFetcher); ok {
+		return rf.FetchResult(ctx)
+	}
+	values, err := p.Fetch(ctx)
+	return FetchResult{Values: values}, err
+}
+
+// Current implements Loader.Current. It takes no lock, so hot read paths
+// do not contend with each other or with a reload.
 func (l *loader[T]) Current() *T {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
-	return l.current
+	return l.current.Load()
 }
 
 // OnChange implements Loader.OnChange.
@@ -216,8 +919,154 @@ func (l *loader[T]) Metadata() ConfigMetadata {
 	return l.metadata
 }
 
+// Version implements Loader.Version.
+func (l *loader[T]) Version() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.version
+}
+
+// Warnings implements Loader.Warnings.
+func (l *loader[T]) Warnings() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return slices.Clone(l.metadata.Warnings)
+}
+
+// Stale implements Loader.Stale.
+func (l *loader[T]) Stale() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.stale
+}
+
+// LastError implements Loader.LastError.
+func (l *loader[T]) LastError() error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.lastErr
+}
+
+// Providers implements Loader.Providers.
+func (l *loader[T]) Providers() (primary, fallback Provider) {
+	return l.provider, l.fallback
+}
+
+// DebugString implements Loader.DebugString.
+func (l *loader[T]) DebugString() string {
+	return debugString(l.Metadata(), l.provider, l.Stale())
+}
+
+// debugString formats the DebugString summary. Only metadata is printed,
+// never values or warning text, which can quote a rejected value.
+func debugString(meta ConfigMetadata, primary Provider, stale bool) string {
+	var b strings.Builder
+	source := meta.Source
+	if source == "" {
+		source = "(not loaded)"
+	}
+	fmt.Fprintf(&b, "source: %s\n", source)
+	if meta.Project != "" || meta.Config != "" {
+		fmt.Fprintf(&b, "project: %s\nconfig: %s\n", meta.Project, meta.Config)
+	}
+	if !meta.LoadedAt.IsZero() {
+		fmt.Fprintf(&b, "loaded_at: %s\n", meta.LoadedAt.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "keys: %d\n", meta.KeyCount)
+	if cs, ok := primary.(circuitStater); ok {
+		fmt.Fprintf(&b, "circuit: %s\n", circuitStateName(cs.CircuitState()))
+	}
+	fmt.Fprintf(&b, "stale: %t\n", stale)
+	fmt.Fprintf(&b, "warnings: %d", len(meta.Warnings))
+	return b.String()
+}
+
+// circuitStateName returns a lowercase name for a circuit breaker state.
+func circuitStateName(s call.State) string {
+	switch s {
+	case call.StateClosed:
+		return "closed"
+	case call.StateOpen:
+		return "open"
+	case call.StateHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("unknown(%d)", s)
+}
+
+// VerifyKeys implements Loader.VerifyKeys.
+func (l *loader[T]) VerifyKeys(ctx context.Context, keys []string) ([]string, error) {
+	var names []string
+	var err error
+	for _, p := range []Provider{l.provider, l.fallback} {
+		if p == nil {
+			continue
+		}
+		if names, err = fetchNames(ctx, p); err == nil {
+			break
+		}
+	}
+	if names == nil && err == nil {
+		return nil, errNoProviders
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	present := make(map[string]bool, len(names))
+	for _, name := range names {
+		if l.keyTransform != nil {
+			name = l.keyTransform(name)
+		}
+		if l.caseInsensitive {
+			name = strings.ToUpper(name)
+		}
+		present[name] = true
+	}
+	var missing []string
+	for _, key := range keys {
+		k := key
+		if l.caseInsensitive {
+			k = strings.ToUpper(k)
+		}
+		if !present[k] {
+			missing = append(missing, key)
+		}
+	}
+	return missing, nil
+}
+
+// fetchNames lists p's keys, using NameFetcher when p implements it.
+func fetchNames(ctx context.Context, p Provider) ([]string, error) {
+	if nf, ok := p.(NameFetcher); ok {
+		return nf.FetchNames(ctx)
+	}
+	values, err := p.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for k := range values {
+		names = append(names, k)
+	}
+	return names, nil
+}
+
 // Close implements Loader.Close.
 func (l *loader[T]) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	l.mu.Unlock()
+
+	// Cancel in-flight loads and wait for them to unwind before closing
+	// the providers they are using.
+	l.closeCancel()
+	l.inflight.Wait()
+
 	var errs []error
 	if l.provider != nil {
 		if err := l.provider.Close(); err != nil {
@@ -235,77 +1084,409 @@ func (l *loader[T]) Close() error {
 	return nil
 }
 
+// mergeCancel returns a context derived from ctx that is also cancelled when
+// other is done.
+func mergeCancel(ctx, other context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(other, func() {
+		cancel(context.Cause(other))
+	})
+	return ctx, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+var (
+	defaultFuncsMu sync.RWMutex
+	defaultFuncs   = make(map[string]func() string)
+)
+
+// RegisterDefaultFunc registers fn to compute the default for the doppler key
+// when the provider does not supply a value, for defaults that cannot be
+// static strings (e.g. derived from os.Hostname). Passing a nil fn removes
+// the registration. If fn returns "", the `default:` tag is used instead.
+//
+// Precedence: provider value > WithDefaults > default func > `default:` tag.
+func RegisterDefaultFunc(key string, fn func() string) {
+	defaultFuncsMu.Lock()
+	defer defaultFuncsMu.Unlock()
+	if fn == nil {
+		delete(defaultFuncs, key)
+		return
+	}
+	defaultFuncs[key] = fn
+}
+
+// lookupDefaultFunc returns the default func registered for key, if any.
+func lookupDefaultFunc(key string) func() string {
+	defaultFuncsMu.RLock()
+	defer defaultFuncsMu.RUnlock()
+	return defaultFuncs[key]
+}
+
 // unmarshalConfig populates a struct from a map using reflection.
 // Returns warnings for non-fatal issues.
 func unmarshalConfig(values map[string]string, target any) ([]string, error) {
-	var warnings []string
+	d := newDecoder(values)
+	if err := d.decode(target); err != nil {
+		return d.warnings, err
+	}
+	return d.warnings, nil
+}
+
+// decoder carries the state of a single unmarshal pass: the source values,
+// accumulated warnings, and the set of keys consumed by struct fields.
+type decoder struct {
+	values   map[string]string
+	warnings []string
+	used     map[string]bool
+
+	// parseErrors mirrors the field-level warnings as ValidationErrors
+	// (Code "parse") for LoadAndValidate.
+	parseErrors ValidationErrors
+
+	// skipRequired disables the missing-required-field error.
+	skipRequired bool
+
+	// rejectEmptyRequired makes a present but empty required value an
+	// error; see WithRejectEmptyRequired.
+	rejectEmptyRequired bool
+
+	// profile is the config name that selects profile tag defaults.
+	profile string
 
+	// logger, when set, receives a debug line per field describing how
+	// its key was resolved.
+	logger *slog.Logger
+
+	// optionalDepth counts the optional sections being decoded; missing
+	// required fields inside them are held in pendingRequired until the
+	// section turns out to be configured.
+	optionalDepth   int
+	pendingRequired []error
+
+	// nestedSep joins nested struct key segments. When empty, a tagged
+	// parent's prefix uses "_" and untagged parents use "Parent.Child".
+	nestedSep string
+
+	// foldIndex maps lowercased keys to their original form. It is nil
+	// unless case-insensitive matching is enabled.
+	foldIndex map[string]string
+}
+
+func newDecoder(values map[string]string) *decoder {
+	return &decoder{
+		values: values,
+		used:   make(map[string]bool),
+	}
+}
+
+// enableCaseInsensitive builds the lowercased key index used by lookup.
+// When several keys differ only in case, the one sorting first wins.
+func (d *decoder) enableCaseInsensitive() {
+	keys := make([]string, 0, len(d.values))
+	for k := range d.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	d.foldIndex = make(map[string]string, len(keys))
+	for _, k := range keys {
+		lower := strings.ToLower(k)
+		if _, ok := d.foldIndex[lower]; !ok {
+			d.foldIndex[lower] = k
+		}
+	}
+}
+
+// lookup returns the value for key, marking it used. An exact match is
+// preferred; with case-insensitive matching enabled, a key differing only
+// in case is accepted.
+func (d *decoder) lookup(key string) (string, bool) {
+	if value, ok := d.values[key]; ok {
+		d.used[key] = true
+		return value, true
+	}
+	if d.foldIndex != nil {
+		if original, ok := d.foldIndex[strings.ToLower(key)]; ok {
+			d.used[original] = true
+			return d.values[original], true
+		}
+	}
+	return "", false
+}
+
+// addParseError records a field whose value could not be parsed. Values of
+// secret fields are redacted.
+func (d *decoder) addParseError(name string, field reflect.StructField, raw, msg string) {
+	var value any = raw
+	if isSecretField(field) {
+		value = NewSecretValue(raw)
+	}
+	d.parseErrors = append(d.parseErrors, ValidationError{
+		Field:   name,
+		Value:   value,
+		Message: msg,
+		Code:    "parse",
+	})
+}
+
+// isSecretField reports whether field holds a secret: tagged secret:"true"
+// or of type SecretValue.
+func isSecretField(field reflect.StructField) bool {
+	return field.Tag.Get(TagSecret) == "true" || field.Type == reflect.TypeOf(SecretValue{})
+}
+
+// redactParseError returns err's message with the raw value masked if the
+// field holds a secret.
+func redactParseError(field reflect.StructField, raw string, err error) string {
+	msg := err.Error()
+	if isSecretField(field) {
+		msg = strings.ReplaceAll(msg, raw, "[REDACTED]")
+	}
+	return msg
+}
+
+// profileEntries parses a profile tag ("dev=debug;prd=warn") into config
+// name -> default value. Entries without "=" or a name are returned in bad.
+func profileEntries(tag string) (entries map[string]string, bad []string) {
+	entries = make(map[string]string)
+	for _, entry := range strings.Split(tag, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			bad = append(bad, entry)
+			continue
+		}
+		entries[name] = value
+	}
+	return entries, bad
+}
+
+// profileDefault returns the profile tag's default for config: the entry
+// named config, else the one named after its root, since Doppler names
+// branch configs root_branch.
+func profileDefault(tag, config string) (string, bool) {
+	if tag == "" || config == "" {
+		return "", false
+	}
+	entries, _ := profileEntries(tag)
+	if value, ok := entries[config]; ok {
+		return value, true
+	}
+	if root, _, ok := strings.Cut(config, "_"); ok {
+		if value, ok := entries[root]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// decode populates target, which must be a non-nil pointer to a struct.
+func (d *decoder) decode(target any) error {
 	v := reflect.ValueOf(target)
 	if v.Kind() != reflect.Ptr || v.IsNil() {
-		return nil, fmt.Errorf("target must be a non-nil pointer")
+		return fmt.Errorf("target must be a non-nil pointer")
 	}
 	v = v.Elem()
 
 	if v.Kind() != reflect.Struct {
-		return nil, fmt.Errorf("target must be a pointer to struct")
+		return fmt.Errorf("target must be a pointer to struct")
+	}
+
+	return d.unmarshalStruct(v, "", "")
+}
+
+// tagSeparator returns the separator placed after a tagged parent's prefix.
+func (d *decoder) tagSeparator() string {
+	if d.nestedSep != "" {
+		return d.nestedSep
+	}
+	return "_"
+}
+
+// tagKey returns the field's doppler tag, falling back to the env tag for
+// chassis-go compatibility.
+func tagKey(field reflect.StructField) string {
+	if key := field.Tag.Get(TagDoppler); key != "" {
+		return key
+	}
+	return field.Tag.Get(TagEnv)
+}
+
+// unusedKeys returns the sorted provider keys that no struct field consumed,
+// skipping keys in ignore and Doppler's auto-injected DOPPLER_* keys.
+func (d *decoder) unusedKeys(ignore map[string]bool) []string {
+	var unused []string
+	for key := range d.values {
+		if d.used[key] || ignore[key] || strings.HasPrefix(key, "DOPPLER_") {
+			continue
+		}
+		unused = append(unused, key)
 	}
+	sort.Strings(unused)
+	return unused
+}
 
-	return unmarshalStruct(values, v, "", &warnings)
+// unmarshalOptional decodes a nested struct tagged optional:"true". Its
+// missing required fields are only an error if the section ends up with
+// any field set; an entirely zero section was left out on purpose.
+func (d *decoder) unmarshalOptional(v reflect.Value, prefix, keyPrefix string) error {
+	mark := len(d.pendingRequired)
+	d.optionalDepth++
+	err := d.unmarshalStruct(v, prefix, keyPrefix)
+	d.optionalDepth--
+	if err != nil {
+		return err
+	}
+
+	pending := d.pendingRequired[mark:]
+	d.pendingRequired = d.pendingRequired[:mark]
+	if len(pending) == 0 || v.IsZero() {
+		return nil
+	}
+	if d.optionalDepth > 0 {
+		d.pendingRequired = append(d.pendingRequired, pending...)
+		return nil
+	}
+	return pending[0]
 }
 
-func unmarshalStruct(values map[string]string, v reflect.Value, prefix string, warnings *[]string) ([]string, error) {
+// unmarshalStruct fills v's fields. prefix is the dotted field path used in
+// messages and for untagged fields; keyPrefix is set inside a nested struct
+// whose field carries a doppler or env tag, e.g. "REDIS_" for
+// `Redis RedisConfig doppler:"REDIS"`, and is prepended to every child key
+// so nested sections of a flattened file line up with the struct. See
+// WithNestedSeparator.
+func (d *decoder) unmarshalStruct(v reflect.Value, prefix, keyPrefix string) error {
 	t := v.Type()
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 		fieldValue := v.Field(i)
 
-		// Skip unexported fields
-		if !fieldValue.CanSet() {
+		// Skip unexported fields, and fields opted out with doppler:"-"
+		if !fieldValue.CanSet() || tagKey(field) == "-" {
 			continue
 		}
 
+		// Fields that decode themselves from JSON take one key as a whole
+		decodeJSON := field.Tag.Get(TagEncoding) == "json" || isJSONUnmarshaler(field.Type)
+
 		// Handle embedded/nested structs
-		if field.Type.Kind() == reflect.Struct && field.Anonymous {
-			if _, err := unmarshalStruct(values, fieldValue, prefix, warnings); err != nil {
-				return *warnings, err
+		if field.Type.Kind() == reflect.Struct && field.Anonymous && !decodeJSON {
+			if err := d.unmarshalStruct(fieldValue, prefix, keyPrefix); err != nil {
+				return err
 			}
 			continue
 		}
 
 		// Handle nested structs (non-anonymous)
-		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) && field.Type != reflect.TypeOf(SecretValue{}) {
-			newPrefix := prefix + field.Name + "."
-			if _, err := unmarshalStruct(values, fieldValue, newPrefix, warnings); err != nil {
-				return *warnings, err
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) && field.Type != reflect.TypeOf(SecretValue{}) && !decodeJSON {
+			newKeyPrefix := keyPrefix
+			if tag := tagKey(field); tag != "" {
+				newKeyPrefix = keyPrefix + tag + d.tagSeparator()
+			}
+			if field.Tag.Get(TagOptional) == "true" {
+				if err := d.unmarshalOptional(fieldValue, prefix+field.Name+".", newKeyPrefix); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := d.unmarshalStruct(fieldValue, prefix+field.Name+".", newKeyPrefix); err != nil {
+				return err
 			}
 			continue
 		}
 
-		// Get the doppler tag, falling back to env tag for chassis-go compatibility
-		dopplerKey := field.Tag.Get(TagDoppler)
-		if dopplerKey == "" {
-			dopplerKey = field.Tag.Get(TagEnv)
-		}
-		if dopplerKey == "" {
+		dopplerKey := tagKey(field)
+		switch {
+		case dopplerKey != "":
+			dopplerKey = keyPrefix + dopplerKey
+		case keyPrefix != "":
+			dopplerKey = keyPrefix + field.Name
+		case d.nestedSep != "":
+			dopplerKey = strings.ReplaceAll(prefix, ".", d.nestedSep) + field.Name
+		default:
 			dopplerKey = prefix + field.Name
 		}
 
+		if field.Tag.Get(TagCollect) == "prefix" {
+			if err := d.collectPrefix(fieldValue, field, prefix, dopplerKey); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Slice && strings.Contains(dopplerKey, "#") {
+			if d.collectNumbered(fieldValue, field, prefix, dopplerKey) {
+				continue
+			}
+		}
+
 		// Get the value
-		rawValue, exists := values[dopplerKey]
+		rawValue, exists := d.lookup(dopplerKey)
+		found := exists
+		source := "provider"
 
-		// Use default if not found
+		if d.rejectEmptyRequired && exists && rawValue == "" && field.Tag.Get(TagRequired) == "true" {
+			err := fmt.Errorf("required field %s (key: %s) is set but empty: %w", field.Name, dopplerKey, ErrRequiredEmpty)
+			if d.optionalDepth == 0 {
+				return err
+			}
+			d.pendingRequired = append(d.pendingRequired, err)
+		}
+
+		// Use default if not found: a registered default func wins over
+		// the static default tag.
 		if !exists || rawValue == "" {
 			defaultValue := field.Tag.Get(TagDefault)
+			source = "default_tag"
+			if value, ok := profileDefault(field.Tag.Get(TagProfile), d.profile); ok {
+				defaultValue = value
+				source = "default_profile"
+			}
+			if fn := lookupDefaultFunc(dopplerKey); fn != nil {
+				if computed := fn(); computed != "" {
+					defaultValue = computed
+					source = "default_func"
+				}
+			}
 			if defaultValue != "" {
 				rawValue = defaultValue
 				exists = true
+			} else {
+				source = "unset"
+			}
+		}
+
+		if d.logger != nil {
+			var value any = rawValue
+			if isSecretField(field) {
+				value = NewSecretValue(rawValue)
 			}
+			d.logger.Debug("config key resolved",
+				"field", prefix+field.Name,
+				"key", dopplerKey,
+				"found", found,
+				"default_used", strings.HasPrefix(source, "default_"),
+				"source", source,
+				"value", value,
+			)
 		}
 
 		// Check required
-		if field.Tag.Get(TagRequired) == "true" && !exists {
-			return *warnings, fmt.Errorf("required field %s (key: %s) not found", field.Name, dopplerKey)
+		if field.Tag.Get(TagRequired) == "true" && !exists && !d.skipRequired {
+			err := fmt.Errorf("required field %s (key: %s) not found", field.Name, dopplerKey)
+			if d.optionalDepth == 0 {
+				return err
+			}
+			d.pendingRequired = append(d.pendingRequired, err)
 		}
 
 		// Skip if no value
@@ -314,15 +1495,147 @@ func unmarshalStruct(values map[string]string, v reflect.Value, prefix string, w
 		}
 
 		// Set the value
+		if decodeJSON {
+			if err := json.Unmarshal([]byte(rawValue), fieldValue.Addr().Interface()); err != nil {
+				msg := redactParseError(field, rawValue, err)
+				d.warnings = append(d.warnings, fmt.Sprintf("failed to decode JSON for %s: %s", field.Name, msg))
+				d.addParseError(prefix+field.Name, field, rawValue, msg)
+			}
+			continue
+		}
 		if err := setFieldValue(fieldValue, rawValue); err != nil {
-			*warnings = append(*warnings, fmt.Sprintf("failed to set %s: %v", field.Name, err))
+			msg := redactParseError(field, rawValue, err)
+			d.warnings = append(d.warnings, fmt.Sprintf("failed to set %s: %s", field.Name, msg))
+			d.addParseError(prefix+field.Name, field, rawValue, msg)
 		}
 	}
 
-	return *warnings, nil
+	return nil
+}
+
+// collectPrefix fills a map field tagged collect:"prefix" with every key
+// starting with keyPrefix, keyed by the lowercased remainder, so
+// WEBHOOK_URL_SLACK lands under "slack" for doppler:"WEBHOOK_URL_". Values
+// are parsed into the map's element type; one that fails is skipped with a
+// warning.
+func (d *decoder) collectPrefix(v reflect.Value, field reflect.StructField, prefix, keyPrefix string) error {
+	t := field.Type
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return fmt.Errorf("field %s: collect:\"prefix\" requires a map with string keys, got %s", field.Name, t)
+	}
+
+	keys := make([]string, 0, len(d.values))
+	for k := range d.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	m := reflect.MakeMap(t)
+	for _, k := range keys {
+		matched := strings.HasPrefix(k, keyPrefix) ||
+			d.foldIndex != nil && strings.HasPrefix(strings.ToLower(k), strings.ToLower(keyPrefix))
+		if !matched || len(k) == len(keyPrefix) {
+			continue
+		}
+		suffix := k[len(keyPrefix):]
+		d.used[k] = true
+		elem := reflect.New(t.Elem()).Elem()
+		if err := setFieldValue(elem, d.values[k]); err != nil {
+			msg := redactParseError(field, d.values[k], err)
+			d.warnings = append(d.warnings, fmt.Sprintf("failed to set %s[%s]: %s", field.Name, k, msg))
+			d.addParseError(prefix+field.Name, field, d.values[k], msg)
+			continue
+		}
+		m.SetMapIndex(reflect.ValueOf(strings.ToLower(suffix)).Convert(t.Key()), elem)
+	}
+
+	if m.Len() == 0 {
+		if field.Tag.Get(TagRequired) == "true" && !d.skipRequired {
+			err := fmt.Errorf("required field %s (prefix: %s) matched no keys", field.Name, keyPrefix)
+			if d.optionalDepth == 0 {
+				return err
+			}
+			d.pendingRequired = append(d.pendingRequired, err)
+		}
+		return nil
+	}
+	v.Set(m)
+	return nil
+}
+
+// collectNumbered fills a slice field whose key contains "#" from numbered
+// keys, so doppler:"UPSTREAM_#" reads UPSTREAM_1, UPSTREAM_2, ... until the
+// first missing one. Each key holds one element, parsed into the slice's
+// element type; one that fails is left zero with a warning. It returns false
+// if UPSTREAM_1 is absent, leaving the field to the usual default and
+// required handling.
+func (d *decoder) collectNumbered(v reflect.Value, field reflect.StructField, prefix, pattern string) bool {
+	s := reflect.MakeSlice(field.Type, 0, 0)
+	for i := 1; ; i++ {
+		key := strings.Replace(pattern, "#", strconv.Itoa(i), 1)
+		raw, ok := d.lookup(key)
+		if !ok {
+			break
+		}
+		elem := reflect.New(field.Type.Elem()).Elem()
+		if err := setFieldValue(elem, raw); err != nil {
+			msg := redactParseError(field, raw, err)
+			d.warnings = append(d.warnings, fmt.Sprintf("failed to set %s[%d] (key: %s): %s", field.Name, i-1, key, msg))
+			d.addParseError(prefix+field.Name, field, raw, msg)
+		}
+		s = reflect.Append(s, elem)
+	}
+	if s.Len() == 0 {
+		return false
+	}
+	v.Set(s)
+	return true
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// isJSONUnmarshaler reports whether t, or a pointer to t, implements
+// json.Unmarshaler. time.Time is excluded: its JSON form is a quoted string,
+// which Doppler values do not carry.
+func isJSONUnmarshaler(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return false
+	}
+	return t.Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(jsonUnmarshalerType)
+}
+
+// parseBoolStrict parses the boolean spellings shared by config fields and
+// feature flags, ignoring case and surrounding space:
+//
+//	true:  true, t, 1, yes, y, on, enabled, enable
+//	false: false, f, 0, no, n, off, disabled, disable
+//
+// ok is false for anything else; callers decide what an unknown value means.
+func parseBoolStrict(s string) (value, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "t", "1", "yes", "y", "on", "enabled", "enable":
+		return true, true
+	case "false", "f", "0", "no", "n", "off", "disabled", "disable":
+		return false, true
+	}
+	return false, false
 }
 
 func setFieldValue(v reflect.Value, s string) error {
+	// A pointer field is allocated and its element set, so a present key
+	// is distinguishable from an absent one (left nil).
+	if v.Kind() == reflect.Ptr {
+		elem := reflect.New(v.Type().Elem())
+		if err := setFieldValue(elem.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
 	switch v.Kind() {
 	case reflect.String:
 		v.SetString(s)
@@ -330,14 +1643,9 @@ func setFieldValue(v reflect.Value, s string) error {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		// Handle time.Duration specially
 		if v.Type() == reflect.TypeOf(time.Duration(0)) {
-			d, err := time.ParseDuration(s)
+			d, err := parseDuration(s)
 			if err != nil {
-				// Try parsing as seconds
-				secs, err := strconv.ParseInt(s, 10, 64)
-				if err != nil {
-					return fmt.Errorf("invalid duration: %s", s)
-				}
-				d = time.Duration(secs) * time.Second
+				return err
 			}
 			v.SetInt(int64(d))
 			return nil
@@ -364,17 +1672,9 @@ func setFieldValue(v reflect.Value, s string) error {
 		v.SetFloat(f)
 
 	case reflect.Bool:
-		b, err := strconv.ParseBool(s)
-		if err != nil {
-			// Accept common variations
-			switch strings.ToLower(s) {
-			case "yes", "y", "on", "enabled", "1":
-				b = true
-			case "no", "n", "off", "disabled", "0":
-				b = false
-			default:
-				return fmt.Errorf("invalid boolean: %s", s)
-			}
+		b, ok := parseBoolStrict(s)
+		if !ok {
+			return fmt.Errorf("invalid boolean: %s", s)
 		}
 		v.SetBool(b)
 
@@ -386,37 +1686,37 @@ func setFieldValue(v reflect.Value, s string) error {
 		}
 
 		elemType := v.Type().Elem()
+
+		// Handle []time.Duration specially
+		if elemType == reflect.TypeOf(time.Duration(0)) {
+			return setSlice(v, parts, "duration", func(elem reflect.Value, p string) bool {
+				d, err := parseDuration(p)
+				elem.SetInt(int64(d))
+				return err == nil
+			})
+		}
+
 		switch elemType.Kind() {
 		case reflect.String:
 			v.Set(reflect.ValueOf(parts))
 		case reflect.Int:
-			ints := make([]int, len(parts))
-			for i, p := range parts {
+			return setSlice(v, parts, "int", func(elem reflect.Value, p string) bool {
 				val, err := strconv.Atoi(p)
-				if err != nil {
-					return fmt.Errorf("invalid int in slice: %s", p)
-				}
-				ints[i] = val
-			}
-			v.Set(reflect.ValueOf(ints))
+				elem.SetInt(int64(val))
+				return err == nil
+			})
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return setSlice(v, parts, "unsigned integer", func(elem reflect.Value, p string) bool {
+				val, err := strconv.ParseUint(p, 10, elemType.Bits())
+				elem.SetUint(val)
+				return err == nil
+			})
 		case reflect.Bool:
-			bools := make([]bool, len(parts))
-			for i, p := range parts {
-				val, err := strconv.ParseBool(p)
-				if err != nil {
-					// Handle alternate boolean values
-					switch strings.ToLower(p) {
-					case "yes", "y", "on", "enabled", "1":
-						val = true
-					case "no", "n", "off", "disabled", "0":
-						val = false
-					default:
-						return fmt.Errorf("invalid bool in slice: %s", p)
-					}
-				}
-				bools[i] = val
-			}
-			v.Set(reflect.ValueOf(bools))
+			return setSlice(v, parts, "bool", func(elem reflect.Value, p string) bool {
+				val, ok := parseBoolStrict(p)
+				elem.SetBool(val)
+				return ok
+			})
 		default:
 			return fmt.Errorf("unsupported slice type: %v", v.Type())
 		}
@@ -435,3 +1735,37 @@ func setFieldValue(v reflect.Value, s string) error {
 
 	return nil
 }
+
+// setSlice parses each element of parts into a new slice of v's type and
+// sets v to it. Every element that fails to parse is reported, with its
+// index, in a single error such as "invalid duration in slice: bogus
+// (element 1), xx (element 3)", and v is then left unset.
+func setSlice(v reflect.Value, parts []string, what string, parse func(elem reflect.Value, s string) bool) error {
+	out := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+	var bad []string
+	for i, p := range parts {
+		if !parse(out.Index(i), p) {
+			bad = append(bad, fmt.Sprintf("%s (element %d)", p, i))
+		}
+	}
+	if len(bad) > 0 {
+		return fmt.Errorf("invalid %s in slice: %s", what, strings.Join(bad, ", "))
+	}
+	v.Set(out)
+	return nil
+}
+
+// parseDuration parses a Go duration string, falling back to a plain
+// integer number of seconds.
+func parseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		// Try parsing as seconds
+		secs, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+		d = time.Duration(secs) * time.Second
+	}
+	return d, nil
+}
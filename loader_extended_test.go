@@ -2,12 +2,22 @@ package dopplerconfig
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 type SliceConfig struct {
-	Ints  []int  `doppler:"INTS"`
-	Bools []bool `doppler:"BOOLS"`
+	Ints      []int           `doppler:"INTS"`
+	Bools     []bool          `doppler:"BOOLS"`
+	Uints     []uint          `doppler:"UINTS"`
+	Ports     []uint16        `doppler:"PORTS"`
+	Durations []time.Duration `doppler:"DURATIONS"`
 }
 
 func TestLoader_ExtendedSlices(t *testing.T) {
@@ -42,3 +52,487 @@ func TestLoader_ExtendedSlices(t *testing.T) {
 		}
 	}
 }
+
+func TestLoader_UintAndDurationSlices(t *testing.T) {
+	values := map[string]string{
+		"UINTS":     "80, 443, 8080",
+		"PORTS":     "1,65535",
+		"DURATIONS": "1s, 500ms, 2m, 30",
+	}
+
+	loader, _ := TestLoader[SliceConfig](values)
+	cfg, err := loader.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	expectedUints := []uint{80, 443, 8080}
+	if len(cfg.Uints) != len(expectedUints) {
+		t.Fatalf("Uints = %v, want %v", cfg.Uints, expectedUints)
+	}
+	for i, v := range expectedUints {
+		if cfg.Uints[i] != v {
+			t.Errorf("Uints[%d] = %d, want %d", i, cfg.Uints[i], v)
+		}
+	}
+
+	if len(cfg.Ports) != 2 || cfg.Ports[0] != 1 || cfg.Ports[1] != 65535 {
+		t.Errorf("Ports = %v, want [1 65535]", cfg.Ports)
+	}
+
+	expectedDurations := []time.Duration{time.Second, 500 * time.Millisecond, 2 * time.Minute, 30 * time.Second}
+	if len(cfg.Durations) != len(expectedDurations) {
+		t.Fatalf("Durations = %v, want %v", cfg.Durations, expectedDurations)
+	}
+	for i, v := range expectedDurations {
+		if cfg.Durations[i] != v {
+			t.Errorf("Durations[%d] = %v, want %v", i, cfg.Durations[i], v)
+		}
+	}
+}
+
+func TestLoader_UintAndDurationSlicesInvalid(t *testing.T) {
+	values := map[string]string{
+		"UINTS":     "80, -1",
+		"PORTS":     "70000",
+		"DURATIONS": "1s, soon",
+	}
+
+	loader, _ := TestLoader[SliceConfig](values)
+	cfg, err := loader.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Uints != nil || cfg.Ports != nil || cfg.Durations != nil {
+		t.Errorf("invalid slices should be left unset, got %v %v %v", cfg.Uints, cfg.Ports, cfg.Durations)
+	}
+
+	warnings := loader.Metadata().Warnings
+	for _, want := range []string{"invalid unsigned integer in slice: -1", "invalid unsigned integer in slice: 70000", "invalid duration in slice: soon"} {
+		found := false
+		for _, w := range warnings {
+			if strings.Contains(w, want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("warnings %v missing %q", warnings, want)
+		}
+	}
+}
+
+func TestLoader_SliceReportsEveryBadElement(t *testing.T) {
+	values := map[string]string{
+		"DURATIONS": "1s,bogus,2m,xx",
+		"UINTS":     "-1, 80, x",
+	}
+
+	loader, _ := TestLoader[SliceConfig](values)
+	cfg, err := loader.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Durations != nil || cfg.Uints != nil {
+		t.Errorf("slices with bad elements should be left unset, got %v %v", cfg.Durations, cfg.Uints)
+	}
+
+	warnings := strings.Join(loader.Metadata().Warnings, "\n")
+	for _, want := range []string{
+		"invalid duration in slice: bogus (element 1), xx (element 3)",
+		"invalid unsigned integer in slice: -1 (element 0), x (element 2)",
+	} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("warnings %q missing %q", warnings, want)
+		}
+	}
+}
+
+type jsonLimits struct {
+	A int      `json:"a"`
+	B []string `json:"b"`
+}
+
+// csvList implements json.Unmarshaler, so it is decoded without a tag.
+type csvList []string
+
+func (c *csvList) UnmarshalJSON(data []byte) error {
+	var items []string
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	*c = items
+	return nil
+}
+
+type JSONFieldConfig struct {
+	Limits  jsonLimits     `doppler:"LIMITS" encoding:"json"`
+	Weights map[string]int `doppler:"WEIGHTS" encoding:"json"`
+	Opt     *jsonLimits    `doppler:"OPT" encoding:"json"`
+	Hosts   csvList        `doppler:"HOSTS"`
+	Broken  jsonLimits     `doppler:"BROKEN" encoding:"json"`
+}
+
+func TestLoader_JSONFields(t *testing.T) {
+	values := map[string]string{
+		"LIMITS":  `{"a":1,"b":["x"]}`,
+		"WEIGHTS": `{"east":3,"west":7}`,
+		"OPT":     `{"a":2}`,
+		"HOSTS":   `["h1","h2"]`,
+		"BROKEN":  `{"a":`,
+	}
+
+	loader, _ := TestLoader[JSONFieldConfig](values)
+	cfg, err := loader.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Limits.A != 1 || len(cfg.Limits.B) != 1 || cfg.Limits.B[0] != "x" {
+		t.Errorf("Limits = %+v, want {A:1 B:[x]}", cfg.Limits)
+	}
+	if cfg.Weights["east"] != 3 || cfg.Weights["west"] != 7 {
+		t.Errorf("Weights = %v, want east=3 west=7", cfg.Weights)
+	}
+	if cfg.Opt == nil || cfg.Opt.A != 2 {
+		t.Errorf("Opt = %+v, want &{A:2}", cfg.Opt)
+	}
+	if len(cfg.Hosts) != 2 || cfg.Hosts[1] != "h2" {
+		t.Errorf("Hosts = %v, want [h1 h2]", cfg.Hosts)
+	}
+
+	found := false
+	for _, w := range loader.Metadata().Warnings {
+		if strings.Contains(w, "failed to decode JSON for Broken") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("warnings %v missing JSON decode failure for Broken", loader.Metadata().Warnings)
+	}
+}
+
+func TestLoader_NestedStructKeyPrefix(t *testing.T) {
+	type RedisConfig struct {
+		Host string `doppler:"HOST"`
+		Port int    `doppler:"PORT" default:"6379"`
+		TLS  struct {
+			Enabled bool `doppler:"ENABLED"`
+		} `doppler:"TLS"`
+	}
+	type cfg struct {
+		Redis RedisConfig `doppler:"REDIS"`
+		Cache RedisConfig `doppler:"CACHE"`
+		Name  string      `doppler:"NAME"`
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"NAME": "svc", "REDIS": {"HOST": "redis.internal", "PORT": 6380, "TLS": {"ENABLED": true}}, "CACHE": {"HOST": "cache.internal"}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewLoaderWithProvider[cfg](NewFileProvider(path), nil)
+	got, err := loader.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got.Redis.Host != "redis.internal" || got.Redis.Port != 6380 || !got.Redis.TLS.Enabled {
+		t.Errorf("Redis = %+v, want values from the REDIS section", got.Redis)
+	}
+	if got.Cache.Host != "cache.internal" || got.Cache.Port != 6379 || got.Cache.TLS.Enabled {
+		t.Errorf("Cache = %+v, want values from the CACHE section", got.Cache)
+	}
+	if got.Name != "svc" {
+		t.Errorf("Name = %q, want %q", got.Name, "svc")
+	}
+}
+
+func TestLoader_WithNestedSeparator(t *testing.T) {
+	type DBConfig struct {
+		Host string `doppler:"HOST"`
+		Port int
+	}
+	type cfg struct {
+		DB    DBConfig `doppler:"DATABASE"`
+		Cache struct {
+			TTL string
+		}
+	}
+
+	tests := []struct {
+		name   string
+		sep    string
+		values map[string]string
+	}{
+		{"underscore", "_", map[string]string{
+			"DATABASE_HOST": "db.internal",
+			"DATABASE_Port": "5433",
+			"Cache_TTL":     "5m",
+		}},
+		{"dot", ".", map[string]string{
+			"DATABASE.HOST": "db.internal",
+			"DATABASE.Port": "5433",
+			"Cache.TTL":     "5m",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := NewLoaderWithProvider[cfg](NewMockProvider(tt.values), nil,
+				WithNestedSeparator[cfg](tt.sep),
+			)
+			got, err := loader.Load(context.Background())
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if got.DB.Host != "db.internal" || got.DB.Port != 5433 || got.Cache.TTL != "5m" {
+				t.Errorf("config = %+v, want every nested key resolved", got)
+			}
+		})
+	}
+}
+
+func TestLoader_OptionalSection(t *testing.T) {
+	type smtpConfig struct {
+		Host string `doppler:"HOST" required:"true"`
+		Port int    `doppler:"PORT" required:"true"`
+	}
+	type appConfig struct {
+		Name string     `doppler:"APP_NAME"`
+		SMTP smtpConfig `doppler:"SMTP" optional:"true"`
+	}
+
+	// Section left out: Load succeeds with a zero section.
+	l := NewLoaderWithProvider[appConfig](NewMockProvider(map[string]string{"APP_NAME": "app"}), nil)
+	cfg, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load without SMTP keys failed: %v", err)
+	}
+	if cfg.SMTP != (smtpConfig{}) {
+		t.Errorf("SMTP = %+v, want zero", cfg.SMTP)
+	}
+
+	// Section partly configured: its required keys are enforced.
+	l = NewLoaderWithProvider[appConfig](NewMockProvider(map[string]string{"SMTP_HOST": "smtp.example.com"}), nil)
+	if _, err := l.Load(context.Background()); err == nil || !strings.Contains(err.Error(), "SMTP_PORT") {
+		t.Errorf("Load with partial SMTP section = %v, want missing SMTP_PORT error", err)
+	}
+
+	l = NewLoaderWithProvider[appConfig](NewMockProvider(map[string]string{"SMTP_HOST": "smtp.example.com", "SMTP_PORT": "587"}), nil)
+	if _, err := l.Load(context.Background()); err != nil {
+		t.Errorf("Load with full SMTP section failed: %v", err)
+	}
+}
+
+func TestLoader_ProfileDefaults(t *testing.T) {
+	type config struct {
+		LogLevel string `doppler:"LOG_LEVEL" profile:"dev=debug;prd=warn" default:"info"`
+		Workers  int    `doppler:"WORKERS" profile:"prd=16" default:"2"`
+	}
+
+	path := filepath.Join(t.TempDir(), "fallback.json")
+	if err := os.WriteFile(path, []byte(`{"WORKERS": ""}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	load := func(t *testing.T, configName string) *config {
+		t.Helper()
+		l, err := NewLoader[config](BootstrapConfig{Config: configName, FallbackPath: path, Offline: true})
+		if err != nil {
+			t.Fatalf("NewLoader failed: %v", err)
+		}
+		defer l.Close()
+		cfg, err := l.Load(context.Background())
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		return cfg
+	}
+
+	tests := []struct {
+		config   string
+		logLevel string
+		workers  int
+	}{
+		{"dev", "debug", 2},
+		{"prd", "warn", 16},
+		{"dev_alice", "debug", 2}, // branch config falls back to its root
+		{"stg", "info", 2},        // no profile entry: plain default
+	}
+	for _, tt := range tests {
+		t.Run(tt.config, func(t *testing.T) {
+			cfg := load(t, tt.config)
+			if cfg.LogLevel != tt.logLevel || cfg.Workers != tt.workers {
+				t.Errorf("LogLevel, Workers = %q, %d; want %q, %d", cfg.LogLevel, cfg.Workers, tt.logLevel, tt.workers)
+			}
+		})
+	}
+
+	// Provided values still win, and WithProfile picks the profile for
+	// loaders without a bootstrap config.
+	l := NewLoaderWithProvider[config](NewMockProvider(map[string]string{"LOG_LEVEL": "error"}), nil, WithProfile[config]("prd"))
+	cfg, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.LogLevel != "error" || cfg.Workers != 16 {
+		t.Errorf("LogLevel, Workers = %q, %d; want error, 16", cfg.LogLevel, cfg.Workers)
+	}
+}
+
+func TestLoader_PointerFields(t *testing.T) {
+	type config struct {
+		MaxConns *int           `doppler:"MAX_CONNS" validate:"min=0"`
+		Debug    *bool          `doppler:"DEBUG"`
+		Name     *string        `doppler:"NAME" required:"true"`
+		Timeout  *time.Duration `doppler:"TIMEOUT" default:"5s"`
+	}
+
+	tests := []struct {
+		name     string
+		values   map[string]string
+		maxConns *int
+		debug    *bool
+	}{
+		{"absent", map[string]string{"NAME": "svc"}, nil, nil},
+		{"explicit zero", map[string]string{"NAME": "svc", "MAX_CONNS": "0", "DEBUG": "false"}, ptr(0), ptr(false)},
+		{"present", map[string]string{"NAME": "svc", "MAX_CONNS": "25", "DEBUG": "true"}, ptr(25), ptr(true)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLoaderWithProvider[config](NewMockProvider(tt.values), nil)
+			cfg, err := l.LoadAndValidate(context.Background())
+			if err != nil {
+				t.Fatalf("LoadAndValidate failed: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.MaxConns, tt.maxConns) {
+				t.Errorf("MaxConns = %v, want %v", deref(cfg.MaxConns), deref(tt.maxConns))
+			}
+			if !reflect.DeepEqual(cfg.Debug, tt.debug) {
+				t.Errorf("Debug = %v, want %v", deref(cfg.Debug), deref(tt.debug))
+			}
+			if cfg.Name == nil || *cfg.Name != "svc" {
+				t.Errorf("Name = %v, want svc", deref(cfg.Name))
+			}
+			if cfg.Timeout == nil || *cfg.Timeout != 5*time.Second {
+				t.Errorf("Timeout = %v, want 5s from the default tag", deref(cfg.Timeout))
+			}
+		})
+	}
+
+	// Rules check the pointed-to value; a nil pointer is unset.
+	l := NewLoaderWithProvider[config](NewMockProvider(map[string]string{"MAX_CONNS": "-1"}), nil)
+	_, err := l.LoadAndValidate(context.Background())
+	for _, want := range []*ValidationError{{Field: "MaxConns", Code: "min"}, {Field: "Name", Code: "required"}} {
+		if !errors.Is(err, want) {
+			t.Errorf("LoadAndValidate error = %v, want %s %s", err, want.Field, want.Code)
+		}
+	}
+}
+
+func ptr[V any](v V) *V { return &v }
+
+func deref[V any](p *V) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func TestLoader_DopplerDashSkipsField(t *testing.T) {
+	type config struct {
+		Name     string            `doppler:"NAME"`
+		Internal string            `doppler:"-" default:"fallback"`
+		Cache    map[string]string `doppler:"-"`
+	}
+	l := NewLoaderWithProvider[config](NewMockProvider(map[string]string{
+		"NAME":     "svc",
+		"-":        "dash",
+		"Internal": "by-name",
+	}), nil)
+
+	cfg, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Name != "svc" {
+		t.Errorf("Name = %q, want svc", cfg.Name)
+	}
+	if cfg.Internal != "" || cfg.Cache != nil {
+		t.Errorf("Internal, Cache = %q, %v; want Go zero values", cfg.Internal, cfg.Cache)
+	}
+}
+
+func TestLoader_CollectPrefix(t *testing.T) {
+	type config struct {
+		Webhooks map[string]string `doppler:"WEBHOOK_URL_" collect:"prefix"`
+		Limits   map[string]int    `doppler:"LIMIT_" collect:"prefix"`
+		Other    string            `doppler:"WEBHOOK_URL"`
+	}
+	l := NewLoaderWithProvider[config](NewMockProvider(map[string]string{
+		"WEBHOOK_URL_SLACK":     "https://hooks.slack.example/x",
+		"WEBHOOK_URL_PAGERDUTY": "https://events.pagerduty.example/y",
+		"WEBHOOK_URL_":          "no-suffix",
+		"WEBHOOK_URL":           "plain",
+		"LIMIT_API":             "100",
+		"LIMIT_BAD":             "lots",
+	}), nil, WithStrictKeys[config]())
+
+	cfg, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	wantHooks := map[string]string{
+		"slack":     "https://hooks.slack.example/x",
+		"pagerduty": "https://events.pagerduty.example/y",
+	}
+	if !reflect.DeepEqual(cfg.Webhooks, wantHooks) {
+		t.Errorf("Webhooks = %v, want %v", cfg.Webhooks, wantHooks)
+	}
+	if !reflect.DeepEqual(cfg.Limits, map[string]int{"api": 100}) {
+		t.Errorf("Limits = %v, want map[api:100]", cfg.Limits)
+	}
+	if cfg.Other != "plain" {
+		t.Errorf("Other = %q, want plain", cfg.Other)
+	}
+
+	warnings := strings.Join(l.Warnings(), "\n")
+	if !strings.Contains(warnings, "LIMIT_BAD") {
+		t.Errorf("expected a warning for LIMIT_BAD, got %q", warnings)
+	}
+	if strings.Contains(warnings, "WEBHOOK_URL_SLACK") {
+		t.Errorf("collected keys should not be reported as unused: %q", warnings)
+	}
+}
+
+func TestLoader_NumberedKeys(t *testing.T) {
+	type config struct {
+		Upstreams []string `doppler:"UPSTREAM_#"`
+		Weights   []int    `doppler:"WEIGHT_#_VALUE"`
+		Mirrors   []string `doppler:"MIRROR_#" default:"a,b"`
+	}
+	l := NewLoaderWithProvider[config](NewMockProvider(map[string]string{
+		"UPSTREAM_1":     "http://a:8080",
+		"UPSTREAM_2":     "http://b:8080,with-comma",
+		"UPSTREAM_3":     "http://c:8080",
+		"UPSTREAM_5":     "http://e:8080",
+		"WEIGHT_1_VALUE": "10",
+		"WEIGHT_2_VALUE": "20",
+	}), nil)
+
+	cfg, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	wantUpstreams := []string{"http://a:8080", "http://b:8080,with-comma", "http://c:8080"}
+	if !reflect.DeepEqual(cfg.Upstreams, wantUpstreams) {
+		t.Errorf("Upstreams = %v, want %v (UPSTREAM_5 is past the gap)", cfg.Upstreams, wantUpstreams)
+	}
+	if !reflect.DeepEqual(cfg.Weights, []int{10, 20}) {
+		t.Errorf("Weights = %v, want [10 20]", cfg.Weights)
+	}
+	if !reflect.DeepEqual(cfg.Mirrors, []string{"a", "b"}) {
+		t.Errorf("Mirrors = %v, want the default [a b]", cfg.Mirrors)
+	}
+}
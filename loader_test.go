@@ -1,8 +1,22 @@
 package dopplerconfig
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/ai8future/chassis-go/v10/call"
 )
 
 // TestConfig is a sample config struct for testing.
@@ -16,7 +30,7 @@ type TestConfig struct {
 		MaxConns int    `doppler:"DATABASE_MAX_CONNS" default:"10"`
 	}
 	Features struct {
-		Enabled  bool     `doppler:"FEATURE_ENABLED" default:"false"`
+		Enabled      bool     `doppler:"FEATURE_ENABLED" default:"false"`
 		AllowedUsers []string `doppler:"FEATURE_ALLOWED_USERS"`
 	}
 	Secret SecretValue `doppler:"API_SECRET"`
@@ -157,7 +171,7 @@ func TestLoader_OnChange(t *testing.T) {
 
 func TestLoader_StringSlice(t *testing.T) {
 	values := map[string]string{
-		"DATABASE_URL":         "postgres://localhost/test",
+		"DATABASE_URL":          "postgres://localhost/test",
 		"FEATURE_ALLOWED_USERS": "user1, user2, user3",
 	}
 
@@ -177,3 +191,1280 @@ func TestLoader_StringSlice(t *testing.T) {
 		}
 	}
 }
+
+func TestNewLoader_Offline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fallback.json")
+	if err := os.WriteFile(path, []byte(`{"DATABASE_URL": "postgres://file/db"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	bootstrap := TestBootstrap()
+	bootstrap.FallbackPath = path
+	bootstrap.Offline = true
+
+	l, err := NewLoader[TestConfig](bootstrap)
+	if err != nil {
+		t.Fatalf("NewLoader failed: %v", err)
+	}
+	if l.(*loader[TestConfig]).provider != nil {
+		t.Error("offline loader should not create a doppler provider")
+	}
+	primary, fallback := l.Providers()
+	if primary != nil {
+		t.Errorf("Providers() primary = %v, want nil offline", primary)
+	}
+	if fallback == nil || fallback.Name() != "file:"+path {
+		t.Errorf("Providers() fallback = %v, want file:%s", fallback, path)
+	}
+
+	cfg, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Database.URL != "postgres://file/db" {
+		t.Errorf("Database.URL = %q, want %q", cfg.Database.URL, "postgres://file/db")
+	}
+	if src := l.Metadata().Source; src != "file:"+path {
+		t.Errorf("Metadata().Source = %q, want %q", src, "file:"+path)
+	}
+}
+
+func TestLoader_Providers(t *testing.T) {
+	mock := NewMockProvider(map[string]string{"DATABASE_URL": "postgres://localhost/test"})
+	fp := NewFileProvider("/etc/app.json")
+
+	primary, fallback := NewLoaderWithProvider[TestConfig](mock, fp).Providers()
+	if primary != mock {
+		t.Errorf("primary = %v, want the mock provider", primary)
+	}
+	if fallback != fp {
+		t.Errorf("fallback = %v, want the file provider", fallback)
+	}
+
+	primary, fallback = NewLoaderWithProvider[TestConfig](mock, nil).Providers()
+	if primary != mock || fallback != nil {
+		t.Errorf("Providers() = (%v, %v), want (mock, nil)", primary, fallback)
+	}
+}
+
+func TestLoader_Warnings(t *testing.T) {
+	mock := NewMockProvider(map[string]string{
+		"DATABASE_URL":       "postgres://localhost/test",
+		"DATABASE_MAX_CONNS": "lots",
+	})
+	l := NewLoaderWithProvider[TestConfig](mock, nil)
+
+	if w := l.Warnings(); len(w) != 0 {
+		t.Errorf("Warnings() before Load = %v, want none", w)
+	}
+	if _, err := l.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	warnings := l.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "MaxConns") {
+		t.Fatalf("Warnings() = %v, want one warning for the bad MaxConns value", warnings)
+	}
+	warnings[0] = "mutated"
+	if l.Warnings()[0] == "mutated" {
+		t.Error("Warnings() should return a copy")
+	}
+}
+
+func TestLoader_WithStrictParse(t *testing.T) {
+	values := map[string]string{
+		"DATABASE_URL":       "postgres://localhost/test",
+		"DATABASE_MAX_CONNS": "lots",
+	}
+
+	// Lenient by default: the field keeps its zero value with a warning.
+	lenient := NewLoaderWithProvider[TestConfig](NewMockProvider(values), nil)
+	cfg, err := lenient.Load(context.Background())
+	if err != nil {
+		t.Fatalf("lenient Load failed: %v", err)
+	}
+	if cfg.Database.MaxConns != 0 || len(lenient.Warnings()) != 1 {
+		t.Errorf("lenient MaxConns = %d, warnings = %v; want 0 and one warning", cfg.Database.MaxConns, lenient.Warnings())
+	}
+
+	strict := NewLoaderWithProvider[TestConfig](NewMockProvider(values), nil, WithStrictParse[TestConfig]())
+	_, err = strict.Load(context.Background())
+	if !errors.Is(err, &ValidationError{Field: "Database.MaxConns", Code: "parse"}) {
+		t.Fatalf("strict Load error = %v, want a parse error for Database.MaxConns", err)
+	}
+	if strict.Current() != nil {
+		t.Error("strict Load should not apply a config with parse errors")
+	}
+}
+
+func TestLoader_WithRejectEmptyRequired(t *testing.T) {
+	type config struct {
+		APIKey string `doppler:"API_KEY" required:"true" default:"dev-key"`
+	}
+	tests := []struct {
+		name      string
+		values    map[string]string
+		want      string
+		wantEmpty bool
+	}{
+		{"missing", map[string]string{}, "dev-key", false},
+		{"present empty", map[string]string{"API_KEY": ""}, "", true},
+		{"present", map[string]string{"API_KEY": "prod-key"}, "prod-key", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lenient := NewLoaderWithProvider[config](NewMockProvider(tt.values), nil)
+			cfg, err := lenient.Load(context.Background())
+			if err != nil {
+				t.Fatalf("lenient Load failed: %v", err)
+			}
+			if tt.wantEmpty && cfg.APIKey != "dev-key" {
+				t.Errorf("lenient APIKey = %q, want the default for an empty value", cfg.APIKey)
+			}
+
+			strict := NewLoaderWithProvider[config](NewMockProvider(tt.values), nil, WithRejectEmptyRequired[config]())
+			cfg, err = strict.Load(context.Background())
+			if tt.wantEmpty {
+				if !errors.Is(err, ErrRequiredEmpty) {
+					t.Fatalf("Load error = %v, want ErrRequiredEmpty", err)
+				}
+				if !strings.Contains(err.Error(), "API_KEY") {
+					t.Errorf("Load error = %v, want it to name the key", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if cfg.APIKey != tt.want {
+				t.Errorf("APIKey = %q, want %q", cfg.APIKey, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLoader_OfflineWithoutFallback(t *testing.T) {
+	bootstrap := TestBootstrap()
+	bootstrap.Offline = true
+
+	if _, err := NewLoader[TestConfig](bootstrap); err == nil {
+		t.Error("NewLoader should fail when offline mode has no fallback")
+	}
+}
+
+func TestLoader_StrictKeys(t *testing.T) {
+	values := map[string]string{
+		"DATABASE_URL":     "postgres://localhost/test",
+		"DATABASE_MAXCONN": "50", // renamed in Doppler, struct still uses DATABASE_MAX_CONNS
+		"LEGACY_FLAG":      "on",
+		"DOPPLER_PROJECT":  "app", // auto-injected by Doppler, never reported
+	}
+
+	mock := NewMockProvider(values)
+	l := NewLoaderWithProvider[TestConfig](mock, nil,
+		WithStrictKeys[TestConfig]("LEGACY_FLAG"),
+	)
+
+	if _, err := l.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	warnings := l.Metadata().Warnings
+	if len(warnings) != 1 {
+		t.Fatalf("Warnings = %v, want exactly one unused-key warning", warnings)
+	}
+	if !strings.Contains(warnings[0], "DATABASE_MAXCONN") {
+		t.Errorf("warning %q should name DATABASE_MAXCONN", warnings[0])
+	}
+}
+
+func TestLoader_StrictKeysDisabledByDefault(t *testing.T) {
+	loader, _ := TestLoader[TestConfig](map[string]string{
+		"DATABASE_URL": "postgres://localhost/test",
+		"UNKNOWN_KEY":  "x",
+	})
+
+	if _, err := loader.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if warnings := loader.Metadata().Warnings; len(warnings) != 0 {
+		t.Errorf("Warnings = %v, want none without WithStrictKeys", warnings)
+	}
+}
+
+func TestLoader_EnvOverrides(t *testing.T) {
+	t.Setenv("DOPPLER_OVERRIDE_SERVER_PORT", "7777")
+	t.Setenv("DOPPLER_OVERRIDE_SERVER_HOST", "override.local")
+
+	values := map[string]string{
+		"SERVER_PORT":  "9090",
+		"DATABASE_URL": "postgres://localhost/test",
+	}
+
+	mock := NewMockProvider(values)
+	l := NewLoaderWithProvider[TestConfig](mock, nil,
+		WithEnvOverrides[TestConfig](""),
+	)
+
+	cfg, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Port != 7777 {
+		t.Errorf("Server.Port = %d, want 7777 (env override beats Doppler)", cfg.Server.Port)
+	}
+	if cfg.Server.Host != "override.local" {
+		t.Errorf("Server.Host = %q, want %q (env override beats default)", cfg.Server.Host, "override.local")
+	}
+	if cfg.Database.URL != "postgres://localhost/test" {
+		t.Errorf("Database.URL = %q, want Doppler value", cfg.Database.URL)
+	}
+}
+
+func TestLoader_FlagOverrides(t *testing.T) {
+	t.Setenv("DOPPLER_OVERRIDE_SERVER_PORT", "7777")
+
+	flags := map[string]string{
+		"SERVER_PORT": "6000",
+		"SERVER_HOST": "flag.local",
+	}
+	mock := NewMockProvider(map[string]string{
+		"SERVER_PORT":  "9090",
+		"DATABASE_URL": "postgres://localhost/test",
+	})
+	l := NewLoaderWithProvider[TestConfig](mock, nil,
+		WithEnvOverrides[TestConfig](""),
+		WithFlagOverrides[TestConfig](flags),
+	)
+	flags["SERVER_HOST"] = "mutated.local" // the option keeps its own copy
+
+	cfg, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Server.Port != 6000 {
+		t.Errorf("Server.Port = %d, want 6000 (flag beats Doppler and env override)", cfg.Server.Port)
+	}
+	if cfg.Server.Host != "flag.local" {
+		t.Errorf("Server.Host = %q, want %q (flag beats default)", cfg.Server.Host, "flag.local")
+	}
+	if cfg.Database.URL != "postgres://localhost/test" {
+		t.Errorf("Database.URL = %q, want Doppler value", cfg.Database.URL)
+	}
+}
+
+func TestLoader_WithValueTransform(t *testing.T) {
+	mock := NewMockProvider(map[string]string{
+		"DATABASE_URL": "  postgres://localhost/test/  ",
+		"SERVER_HOST":  " Example.COM ",
+	})
+	l := NewLoaderWithProvider[TestConfig](mock, nil,
+		WithFlagOverrides[TestConfig](map[string]string{"SERVER_PORT": " 6000 "}),
+		WithValueTransform[TestConfig](func(key, value string) string {
+			value = strings.TrimSpace(value)
+			switch key {
+			case "DATABASE_URL":
+				return strings.TrimSuffix(value, "/")
+			case "SERVER_HOST":
+				return strings.ToLower(value)
+			}
+			return value
+		}),
+	)
+
+	cfg, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Database.URL != "postgres://localhost/test" {
+		t.Errorf("Database.URL = %q, want trimmed URL", cfg.Database.URL)
+	}
+	if cfg.Server.Host != "example.com" {
+		t.Errorf("Server.Host = %q, want normalized host", cfg.Server.Host)
+	}
+	if cfg.Server.Port != 6000 {
+		t.Errorf("Server.Port = %d, want 6000 from the trimmed flag override", cfg.Server.Port)
+	}
+	if w := l.Warnings(); len(w) != 0 {
+		t.Errorf("Warnings() = %v, want none", w)
+	}
+}
+
+func TestLoader_EnvOverridesCustomPrefix(t *testing.T) {
+	t.Setenv("MYAPP_DATABASE_URL", "postgres://override/db")
+
+	mock := NewMockProvider(map[string]string{"DATABASE_URL": "postgres://doppler/db"})
+	l := NewLoaderWithProvider[TestConfig](mock, nil,
+		WithEnvOverrides[TestConfig]("MYAPP_"),
+	)
+
+	cfg, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Database.URL != "postgres://override/db" {
+		t.Errorf("Database.URL = %q, want %q", cfg.Database.URL, "postgres://override/db")
+	}
+}
+
+func TestLoader_WithDefaultsPrecedence(t *testing.T) {
+	values := map[string]string{
+		"DATABASE_URL": "postgres://localhost/test",
+		"SERVER_PORT":  "9090",
+		"SERVER_HOST":  "", // present but empty: programmatic default wins
+	}
+
+	mock := NewMockProvider(values)
+	l := NewLoaderWithProvider[TestConfig](mock, nil,
+		WithDefaults[TestConfig](map[string]string{
+			"SERVER_PORT": "7000",          // provider value wins
+			"SERVER_HOST": "computed-host", // beats empty provider value
+		}),
+	)
+
+	cfg, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Server.Port = %d, want 9090 (provider beats programmatic default)", cfg.Server.Port)
+	}
+	if cfg.Server.Host != "computed-host" {
+		t.Errorf("Server.Host = %q, want %q (programmatic default beats empty)", cfg.Server.Host, "computed-host")
+	}
+	if cfg.Database.MaxConns != 10 {
+		t.Errorf("Database.MaxConns = %d, want tag default 10", cfg.Database.MaxConns)
+	}
+
+	// Programmatic default beats the tag default when the provider omits the key.
+	mock.SetValues(map[string]string{"DATABASE_URL": "postgres://localhost/test"})
+	cfg, err = l.Reload(context.Background())
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if cfg.Server.Port != 7000 {
+		t.Errorf("Server.Port = %d, want 7000 (programmatic default beats tag default)", cfg.Server.Port)
+	}
+}
+
+func TestLoader_StaleAfterFailedReload(t *testing.T) {
+	values := map[string]string{
+		"SERVER_PORT":  "8080",
+		"DATABASE_URL": "postgres://localhost/test",
+	}
+
+	loader, mock := TestLoader[TestConfig](values)
+	cfg, err := loader.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loader.Stale() || loader.LastError() != nil {
+		t.Fatalf("Stale()=%v LastError()=%v after successful load, want false/nil", loader.Stale(), loader.LastError())
+	}
+
+	mock.SetError(fmt.Errorf("doppler unavailable"))
+	if _, err := loader.Reload(context.Background()); err == nil {
+		t.Fatal("Reload should fail when provider errors")
+	}
+
+	if loader.Current() != cfg {
+		t.Error("Current() should still return the last good config after a failed reload")
+	}
+	if !loader.Stale() {
+		t.Error("Stale() = false after failed reload, want true")
+	}
+	if loader.LastError() == nil {
+		t.Error("LastError() = nil after failed reload, want error")
+	}
+
+	// A successful reload clears the stale state.
+	mock.SetError(nil)
+	if _, err := loader.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if loader.Stale() || loader.LastError() != nil {
+		t.Errorf("Stale()=%v LastError()=%v after recovery, want false/nil", loader.Stale(), loader.LastError())
+	}
+}
+
+func TestLoader_FailedInitialLoadNotStale(t *testing.T) {
+	loader, mock := TestLoader[TestConfig](nil)
+	mock.SetError(fmt.Errorf("doppler unavailable"))
+
+	if _, err := loader.Load(context.Background()); err == nil {
+		t.Fatal("Load should fail when provider errors")
+	}
+	if loader.Stale() {
+		t.Error("Stale() = true with no earlier config, want false")
+	}
+	if loader.LastError() == nil {
+		t.Error("LastError() = nil after failed load, want error")
+	}
+}
+
+// flakyProvider fails the first failures fetches, then delegates to values.
+type flakyProvider struct {
+	*MockProvider
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (p *flakyProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	p.mu.Lock()
+	p.calls++
+	fail := p.calls <= p.failures
+	p.mu.Unlock()
+	if fail {
+		return nil, fmt.Errorf("transient failure")
+	}
+	return p.MockProvider.Fetch(ctx)
+}
+
+func TestLoader_WithLoadRetry(t *testing.T) {
+	provider := &flakyProvider{
+		MockProvider: NewMockProvider(map[string]string{"DATABASE_URL": "postgres://localhost/test"}),
+		failures:     2,
+	}
+
+	l := NewLoaderWithProvider[TestConfig](provider, nil,
+		WithLoadRetry[TestConfig](3, time.Millisecond),
+	)
+
+	cfg, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Database.URL != "postgres://localhost/test" {
+		t.Errorf("Database.URL = %q, want loaded value", cfg.Database.URL)
+	}
+	if provider.calls != 3 {
+		t.Errorf("provider calls = %d, want 3 (succeeds on third attempt)", provider.calls)
+	}
+}
+
+func TestLoader_WithLoadRetryExhausted(t *testing.T) {
+	provider := &flakyProvider{MockProvider: NewMockProvider(nil), failures: 10}
+
+	l := NewLoaderWithProvider[TestConfig](provider, nil,
+		WithLoadRetry[TestConfig](2, time.Millisecond),
+	)
+
+	if _, err := l.Load(context.Background()); err == nil {
+		t.Fatal("Load should fail once retries are exhausted")
+	}
+	if provider.calls != 2 {
+		t.Errorf("provider calls = %d, want 2", provider.calls)
+	}
+}
+
+func TestLoader_WithLoadRetryContextCancelled(t *testing.T) {
+	provider := &flakyProvider{MockProvider: NewMockProvider(nil), failures: 10}
+
+	l := NewLoaderWithProvider[TestConfig](provider, nil,
+		WithLoadRetry[TestConfig](5, time.Hour),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := l.Load(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Load error = %v, want context.DeadlineExceeded", err)
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider calls = %d, want 1 (cancelled during backoff)", provider.calls)
+	}
+}
+
+func TestLoader_CloseCancelsInFlightLoad(t *testing.T) {
+	mock := NewMockProvider(map[string]string{"VALUE": "x"})
+	loader := NewLoaderWithProvider[WatchTestConfig](NewSlowProvider(mock, 10*time.Second), nil)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := loader.Load(context.Background())
+		done <- err
+	}()
+
+	// Give the load time to reach the slow fetch.
+	time.Sleep(50 * time.Millisecond)
+
+	if err := loader.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Close waits for the in-flight load, so it has already returned.
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Load error = %v, want context.Canceled", err)
+		}
+	default:
+		t.Fatal("Close returned before the in-flight load finished")
+	}
+
+	if _, err := loader.Load(context.Background()); !errors.Is(err, ErrLoaderClosed) {
+		t.Errorf("Load after Close = %v, want ErrLoaderClosed", err)
+	}
+	if _, err := loader.Reload(context.Background()); !errors.Is(err, ErrLoaderClosed) {
+		t.Errorf("Reload after Close = %v, want ErrLoaderClosed", err)
+	}
+	if err := loader.Close(); err != nil {
+		t.Errorf("second Close = %v, want nil", err)
+	}
+}
+
+func TestRegisterDefaultFunc(t *testing.T) {
+	type cfg struct {
+		BindAddr string `doppler:"TEST_BIND_ADDR" default:"0.0.0.0"`
+		Nonce    string `doppler:"TEST_NONCE" default:"static"`
+	}
+
+	host, _ := os.Hostname()
+	RegisterDefaultFunc("TEST_BIND_ADDR", func() string { return host + ":8080" })
+	RegisterDefaultFunc("TEST_NONCE", func() string { return "" })
+	t.Cleanup(func() {
+		RegisterDefaultFunc("TEST_BIND_ADDR", nil)
+		RegisterDefaultFunc("TEST_NONCE", nil)
+	})
+
+	var c cfg
+	if _, err := unmarshalConfig(map[string]string{}, &c); err != nil {
+		t.Fatalf("unmarshalConfig failed: %v", err)
+	}
+	if c.BindAddr != host+":8080" {
+		t.Errorf("BindAddr = %q, want computed %q", c.BindAddr, host+":8080")
+	}
+	if c.Nonce != "static" {
+		t.Errorf("Nonce = %q, want tag default when func returns empty", c.Nonce)
+	}
+
+	// Provider value wins over the default func.
+	c = cfg{}
+	if _, err := unmarshalConfig(map[string]string{"TEST_BIND_ADDR": "10.0.0.1:80"}, &c); err != nil {
+		t.Fatalf("unmarshalConfig failed: %v", err)
+	}
+	if c.BindAddr != "10.0.0.1:80" {
+		t.Errorf("BindAddr = %q, want provider value", c.BindAddr)
+	}
+}
+
+func TestLoader_WithKeyTransform(t *testing.T) {
+	mock := NewMockProvider(map[string]string{
+		"server/port":  "9090",
+		"database/url": "postgres://localhost/db",
+	})
+	loader := NewLoaderWithProvider[TestConfig](mock, nil,
+		WithKeyTransform[TestConfig](func(key string) string {
+			return strings.ToUpper(strings.ReplaceAll(key, "/", "_"))
+		}),
+	)
+
+	cfg, err := loader.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Server.Port = %d, want 9090 from transformed key", cfg.Server.Port)
+	}
+	if cfg.Database.URL != "postgres://localhost/db" {
+		t.Errorf("Database.URL = %q, want value from transformed key", cfg.Database.URL)
+	}
+
+	// The provider's own map must not be rewritten.
+	if _, ok := mock.values["server/port"]; !ok {
+		t.Error("transform should not mutate provider values")
+	}
+}
+
+func TestLoader_WithCaseInsensitiveKeys(t *testing.T) {
+	values := map[string]string{
+		"server_port":  "9090",
+		"Database_Url": "postgres://localhost/db",
+		"SERVER_HOST":  "exact",
+		"server_host":  "folded",
+	}
+
+	// Exact matching (default) ignores differently-cased keys.
+	exact := NewLoaderWithProvider[TestConfig](NewMockProvider(values), nil)
+	if _, err := exact.Load(context.Background()); err == nil {
+		t.Fatal("expected required DATABASE_URL to be missing without case-insensitive matching")
+	}
+
+	loader := NewLoaderWithProvider[TestConfig](NewMockProvider(values), nil,
+		WithCaseInsensitiveKeys[TestConfig](),
+		WithStrictKeys[TestConfig](),
+	)
+	cfg, err := loader.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Server.Port = %d, want 9090", cfg.Server.Port)
+	}
+	if cfg.Database.URL != "postgres://localhost/db" {
+		t.Errorf("Database.URL = %q, want case-insensitive match", cfg.Database.URL)
+	}
+	if cfg.Server.Host != "exact" {
+		t.Errorf("Server.Host = %q, want exact match to win", cfg.Server.Host)
+	}
+
+	// Keys consumed through a case-insensitive match are not reported unused.
+	for _, w := range loader.Metadata().Warnings {
+		if strings.Contains(w, "server_port") || strings.Contains(w, "Database_Url") {
+			t.Errorf("unexpected unused-key warning: %s", w)
+		}
+	}
+}
+
+func TestLoader_LoadAndValidate(t *testing.T) {
+	type cfg struct {
+		Workers int    `doppler:"WORKERS" validate:"min=1"`
+		Level   string `doppler:"LEVEL" validate:"oneof=debug|info"`
+		Name    string `doppler:"NAME" required:"true"`
+		PIN     int    `doppler:"PIN" secret:"true"`
+	}
+
+	values := map[string]string{
+		"WORKERS": "many",
+		"LEVEL":   "trace",
+		"PIN":     "hunter2",
+	}
+
+	loader := NewLoaderWithProvider[cfg](NewMockProvider(values), nil)
+	_, err := loader.LoadAndValidate(context.Background())
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("LoadAndValidate error = %v, want ValidationErrors", err)
+	}
+	for _, want := range []*ValidationError{
+		{Field: "Workers", Code: "parse"},
+		{Field: "Level", Code: "oneof"},
+		{Field: "Name", Code: "required"},
+		{Field: "PIN", Code: "parse"},
+	} {
+		if !errors.Is(err, want) {
+			t.Errorf("missing %s/%s in %v", want.Field, want.Code, err)
+		}
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Error("secret value should be redacted in validation errors")
+	}
+	if loader.Current() != nil {
+		t.Error("invalid config should not be applied")
+	}
+
+	// Plain Load still reports parse failures as warnings.
+	values["NAME"] = "svc"
+	loader = NewLoaderWithProvider[cfg](NewMockProvider(values), nil)
+	if _, err := loader.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loader.Metadata().Warnings) != 2 {
+		t.Errorf("Warnings = %v, want 2 parse warnings", loader.Metadata().Warnings)
+	}
+}
+
+func TestLoader_DebugResolutionTrace(t *testing.T) {
+	type cfg struct {
+		Host   string      `doppler:"HOST"`
+		Port   int         `doppler:"PORT" default:"8080"`
+		Region string      `doppler:"REGION"`
+		Token  SecretValue `doppler:"TOKEN"`
+		PIN    string      `doppler:"PIN" secret:"true"`
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	provider := NewMockProvider(map[string]string{
+		"HOST":  "db.internal",
+		"TOKEN": "tok-123",
+		"PIN":   "4321",
+	})
+	loader := NewLoaderWithProvider[cfg](provider, nil, WithLoaderLogger[cfg](logger))
+	if _, err := loader.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	traces := make(map[string]map[string]any)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		if rec["msg"] == "config key resolved" {
+			traces[rec["field"].(string)] = rec
+		}
+	}
+
+	tests := []struct {
+		field, key, source string
+		found, defaultUsed bool
+		value              string
+	}{
+		{"Host", "HOST", "provider", true, false, "db.internal"},
+		{"Port", "PORT", "default_tag", false, true, "8080"},
+		{"Region", "REGION", "unset", false, false, ""},
+		{"Token", "TOKEN", "provider", true, false, "[REDACTED]"},
+		{"PIN", "PIN", "provider", true, false, "[REDACTED]"},
+	}
+	for _, tt := range tests {
+		rec, ok := traces[tt.field]
+		if !ok {
+			t.Errorf("no trace line for %s", tt.field)
+			continue
+		}
+		if rec["key"] != tt.key || rec["source"] != tt.source ||
+			rec["found"] != tt.found || rec["default_used"] != tt.defaultUsed || rec["value"] != tt.value {
+			t.Errorf("trace for %s = %v", tt.field, rec)
+		}
+	}
+	if strings.Contains(buf.String(), "tok-123") || strings.Contains(buf.String(), "4321") {
+		t.Error("secret values should be redacted in trace output")
+	}
+
+	// No trace lines above Debug.
+	buf.Reset()
+	logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	loader = NewLoaderWithProvider[cfg](provider, nil, WithLoaderLogger[cfg](logger))
+	if _, err := loader.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "config key resolved") {
+		t.Error("trace should only be emitted at Debug level")
+	}
+}
+
+func TestLoader_WithValidateOnLoad(t *testing.T) {
+	type cfg struct {
+		Port int    `doppler:"PORT" validate:"port"`
+		Name string `doppler:"NAME" required:"true"`
+	}
+
+	mock := NewMockProvider(map[string]string{"PORT": "8080", "NAME": "svc"})
+	loader := NewLoaderWithProvider[cfg](mock, nil, WithValidateOnLoad[cfg]())
+
+	first, err := loader.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	var changed bool
+	loader.OnChange(func(old, new *cfg) { changed = true })
+
+	mock.SetValue("PORT", "70000")
+	_, err = loader.Reload(context.Background())
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Reload error = %v, want ValidationErrors", err)
+	}
+	if !errors.Is(err, &ValidationError{Field: "Port", Code: "port"}) {
+		t.Errorf("Reload error = %v, want a port error on Port", err)
+	}
+	if loader.Current() != first || loader.Current().Port != 8080 {
+		t.Errorf("Current = %+v, want previous config retained", loader.Current())
+	}
+	if changed {
+		t.Error("OnChange should not fire for a rejected reload")
+	}
+
+	mock.SetValue("PORT", "9090")
+	if _, err := loader.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if loader.Current().Port != 9090 || !changed {
+		t.Errorf("Current = %+v, want valid reload applied", loader.Current())
+	}
+}
+
+// circuitProvider is a RecordingProvider that reports a fixed circuit state.
+type circuitProvider struct {
+	*RecordingProvider
+	state call.State
+}
+
+func (p *circuitProvider) CircuitState() call.State { return p.state }
+
+func TestLoader_SkipsPrimaryWhenCircuitOpen(t *testing.T) {
+	primary := &circuitProvider{
+		RecordingProvider: NewRecordingProvider(NewMockProvider(map[string]string{"VALUE": "primary"})),
+		state:             call.StateOpen,
+	}
+	fallback := NewMockProvider(map[string]string{"VALUE": "fallback"})
+
+	loader := NewLoaderWithProvider[WatchTestConfig](primary, fallback)
+	cfg, err := loader.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Value != "fallback" {
+		t.Errorf("Value = %q, want %q", cfg.Value, "fallback")
+	}
+	if got := primary.CallCount(); got != 0 {
+		t.Errorf("primary fetched %d times with an open circuit, want 0", got)
+	}
+
+	// Once the circuit closes the primary is used again.
+	primary.state = call.StateClosed
+	cfg, err = loader.Reload(context.Background())
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if cfg.Value != "primary" || primary.CallCount() != 1 {
+		t.Errorf("Value = %q after %d primary calls, want primary", cfg.Value, primary.CallCount())
+	}
+}
+
+// notModifiedProvider reports its values as not modified while notModified
+// is set.
+type notModifiedProvider struct {
+	*MockProvider
+	mu          sync.Mutex
+	notModified bool
+}
+
+func (p *notModifiedProvider) FetchResult(ctx context.Context) (FetchResult, error) {
+	values, err := p.Fetch(ctx)
+	if err != nil {
+		return FetchResult{}, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return FetchResult{Values: values, NotModified: p.notModified}, nil
+}
+
+func (p *notModifiedProvider) setNotModified(v bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.notModified = v
+}
+
+func TestLoader_NotModifiedSkipsCallbacks(t *testing.T) {
+	provider := &notModifiedProvider{
+		MockProvider: NewMockProvider(map[string]string{"DATABASE_URL": "postgres://localhost/a"}),
+	}
+	l := NewLoaderWithProvider[TestConfig](provider, nil)
+	defer l.Close()
+
+	var changes int
+	l.OnChange(func(old, new *TestConfig) { changes++ })
+
+	ctx := context.Background()
+	first, err := l.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// The provider vouches that nothing changed, so the loader keeps the
+	// current config without looking at the values.
+	provider.setNotModified(true)
+	provider.SetValue("DATABASE_URL", "postgres://localhost/b")
+	cfg, err := l.Reload(ctx)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if cfg != first {
+		t.Error("Reload with NotModified should keep the existing *T")
+	}
+	if changes != 0 {
+		t.Errorf("OnChange called %d times after NotModified reload, want 0", changes)
+	}
+
+	provider.setNotModified(false)
+	cfg, err = l.Reload(ctx)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if cfg == first || cfg.Database.URL != "postgres://localhost/b" {
+		t.Errorf("Reload = %+v, want freshly parsed config", cfg.Database)
+	}
+	if changes != 1 {
+		t.Errorf("OnChange called %d times after modified reload, want 1", changes)
+	}
+}
+
+// blockingProvider counts fetches and holds each one until release is
+// closed, signalling entered first.
+type blockingProvider struct {
+	*MockProvider
+	fetches atomic.Int32
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	p.fetches.Add(1)
+	p.entered <- struct{}{}
+	<-p.release
+	return p.MockProvider.Fetch(ctx)
+}
+
+func TestLoader_ConcurrentReloadsShareFetch(t *testing.T) {
+	provider := &blockingProvider{
+		MockProvider: NewMockProvider(map[string]string{"DATABASE_URL": "postgres://localhost/a"}),
+		entered:      make(chan struct{}, 1),
+		release:      make(chan struct{}),
+	}
+	l := NewLoaderWithProvider[TestConfig](provider, nil)
+	defer l.Close()
+
+	ctx := context.Background()
+	loaded := make(chan error, 1)
+	go func() {
+		_, err := l.Load(ctx)
+		loaded <- err
+	}()
+	<-provider.entered
+	provider.release <- struct{}{}
+	if err := <-loaded; err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	provider.fetches.Store(0)
+
+	provider.SetValue("DATABASE_URL", "postgres://localhost/b")
+	const callers = 8
+	results := make(chan *TestConfig, callers)
+	errs := make(chan error, callers)
+	reload := func() {
+		cfg, err := l.Reload(ctx)
+		results <- cfg
+		errs <- err
+	}
+	go reload()
+	<-provider.entered
+
+	// The first reload is holding its fetch; the rest join it.
+	for i := 1; i < callers; i++ {
+		go reload()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(provider.release)
+
+	var first *TestConfig
+	for i := 0; i < callers; i++ {
+		cfg := <-results
+		if err := <-errs; err != nil {
+			t.Fatalf("Reload failed: %v", err)
+		}
+		if first == nil {
+			first = cfg
+		}
+		if cfg != first {
+			t.Error("concurrent Reload calls returned different configs")
+		}
+	}
+	if first.Database.URL != "postgres://localhost/b" {
+		t.Errorf("Database.URL = %q, want reloaded value", first.Database.URL)
+	}
+	if n := provider.fetches.Load(); n != 1 {
+		t.Errorf("provider fetched %d times, want 1", n)
+	}
+}
+
+// waitForReloadWaiters waits until a Reload is in progress with at least n
+// callers waiting on it.
+func waitForReloadWaiters(t *testing.T, l *loader[TestConfig], n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		l.reloadMu.Lock()
+		got := -1
+		if c := l.reloading; c != nil {
+			got = c.waiters
+		}
+		l.reloadMu.Unlock()
+		if got >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("reload has %d waiters, want %d", got, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLoader_ReloadSurvivesLeaderCancel(t *testing.T) {
+	// The first fetch stalls until the leader's ctx is cancelled.
+	provider := &stallOnceProvider{MockProvider: NewMockProvider(map[string]string{"DATABASE_URL": "postgres://localhost/a"})}
+	l := NewLoaderWithProvider[TestConfig](provider, nil).(*loader[TestConfig])
+	defer l.Close()
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() {
+		_, err := l.Reload(leaderCtx)
+		leader <- err
+	}()
+	waitForReloadWaiters(t, l, 0)
+
+	type result struct {
+		cfg *TestConfig
+		err error
+	}
+	joiner := make(chan result, 1)
+	go func() {
+		cfg, err := l.Reload(context.Background())
+		joiner <- result{cfg, err}
+	}()
+	waitForReloadWaiters(t, l, 1)
+
+	cancelLeader()
+	if err := <-leader; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader error = %v, want context.Canceled", err)
+	}
+	r := <-joiner
+	if r.err != nil {
+		t.Fatalf("joiner Reload failed: %v", r.err)
+	}
+	if r.cfg.Database.URL != "postgres://localhost/a" {
+		t.Errorf("Database.URL = %q, want the reloaded value", r.cfg.Database.URL)
+	}
+	if n := provider.fetches.Load(); n != 2 {
+		t.Errorf("provider fetched %d times, want 2", n)
+	}
+	if l.Current() == nil {
+		t.Error("joiner's reload should be applied")
+	}
+}
+
+func TestLoader_Warmup(t *testing.T) {
+	type config struct {
+		Port int `doppler:"PORT" validate:"port"`
+	}
+
+	flags := NewFeatureFlags(nil, "FEATURE_")
+	bad := NewLoaderWithProvider[config](NewMockProvider(map[string]string{
+		"PORT":      "70000",
+		"FEATURE_X": "true",
+	}), nil, WithFeatureFlags[config](flags))
+	if err := bad.Warmup(context.Background()); err == nil {
+		t.Fatal("Warmup should fail on an invalid config")
+	}
+	if bad.Current() != nil {
+		t.Error("Warmup should not apply an invalid config")
+	}
+	if flags.IsEnabled("x") {
+		t.Error("flags should not be primed from an invalid config")
+	}
+
+	good := NewLoaderWithProvider[config](NewMockProvider(map[string]string{
+		"PORT":      "8080",
+		"FEATURE_X": "true",
+	}), nil, WithFeatureFlags[config](flags))
+	if err := good.Warmup(context.Background()); err != nil {
+		t.Fatalf("Warmup failed: %v", err)
+	}
+	if cfg := good.Current(); cfg == nil || cfg.Port != 8080 {
+		t.Errorf("Current() = %+v, want Port 8080", cfg)
+	}
+	if !flags.IsEnabled("x") {
+		t.Error("Warmup should prime the bound feature flags")
+	}
+	if flags.Source() != "mock" {
+		t.Errorf("flags.Source() = %q, want mock", flags.Source())
+	}
+}
+
+func TestLoader_DebugString(t *testing.T) {
+	type config struct {
+		Password string `doppler:"PASSWORD" secret:"true"`
+		Port     int    `doppler:"PORT"`
+	}
+	l := NewLoaderWithProvider[config](NewMockProvider(map[string]string{
+		"PASSWORD": "hunter2-secret",
+		"PORT":     "not-a-port",
+	}), nil)
+
+	if got := l.DebugString(); !strings.Contains(got, "source: (not loaded)") {
+		t.Errorf("DebugString before Load = %q, want (not loaded) source", got)
+	}
+	if _, err := l.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	got := l.DebugString()
+	for _, want := range []string{"source: mock\n", "keys: 2\n", "stale: false\n", "warnings: 1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("DebugString = %q, missing %q", got, want)
+		}
+	}
+	for _, leaked := range []string{"hunter2-secret", "not-a-port"} {
+		if strings.Contains(got, leaked) {
+			t.Errorf("DebugString leaked value %q: %q", leaked, got)
+		}
+	}
+	if strings.Contains(got, "circuit:") {
+		t.Errorf("DebugString should omit circuit state for providers without a breaker: %q", got)
+	}
+
+	provider, err := NewDopplerProvider("test-token", "proj", "dev")
+	if err != nil {
+		t.Fatalf("NewDopplerProvider failed: %v", err)
+	}
+	defer provider.Close()
+	withBreaker := NewLoaderWithProvider[config](provider, nil)
+	if got := withBreaker.DebugString(); !strings.Contains(got, "circuit: ") {
+		t.Errorf("DebugString = %q, want a circuit line for DopplerProvider", got)
+	}
+}
+
+func TestLoader_ReloadRemovedKeyRevertsToDefault(t *testing.T) {
+	type config struct {
+		Port int    `doppler:"PORT" default:"8080"`
+		Name string `doppler:"NAME"`
+	}
+	var buf bytes.Buffer
+	mock := NewMockProvider(map[string]string{"PORT": "9090", "NAME": "svc"})
+	l := NewLoaderWithProvider[config](mock, nil,
+		WithLoaderLogger[config](slog.New(slog.NewTextHandler(&buf, nil))),
+	)
+
+	ctx := context.Background()
+	cfg, err := l.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Port != 9090 {
+		t.Fatalf("Port = %d, want 9090", cfg.Port)
+	}
+	if len(l.Warnings()) != 0 {
+		t.Errorf("first load should report no removed keys, got %v", l.Warnings())
+	}
+
+	mock.SetValues(map[string]string{"NAME": "svc"})
+	cfg, err = l.Reload(ctx)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("Port = %d, want the default 8080 after PORT was removed", cfg.Port)
+	}
+	warnings := l.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "key PORT removed") {
+		t.Errorf("Warnings = %v, want one naming the removed PORT key", warnings)
+	}
+	if !strings.Contains(buf.String(), "config keys removed since previous load") || !strings.Contains(buf.String(), "PORT") {
+		t.Errorf("log = %q, want a removed-keys warning naming PORT", buf.String())
+	}
+
+	if _, err := l.Reload(ctx); err != nil {
+		t.Fatalf("second Reload failed: %v", err)
+	}
+	if len(l.Warnings()) != 0 {
+		t.Errorf("removal should be reported once, got %v", l.Warnings())
+	}
+}
+
+func TestLoader_Version(t *testing.T) {
+	type config struct {
+		Port int `doppler:"PORT"`
+	}
+	mock := NewMockProvider(map[string]string{"PORT": "8080"})
+	l := NewLoaderWithProvider[config](mock, nil)
+	ctx := context.Background()
+
+	if v := l.Version(); v != 0 {
+		t.Errorf("Version before Load = %d, want 0", v)
+	}
+	if _, err := l.Load(ctx); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if v := l.Version(); v != 1 {
+		t.Errorf("Version after Load = %d, want 1", v)
+	}
+
+	if _, err := l.Reload(ctx); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if v := l.Version(); v != 1 {
+		t.Errorf("Version after identical Reload = %d, want 1", v)
+	}
+
+	mock.SetValue("PORT", "9090")
+	if _, err := l.Reload(ctx); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if v := l.Version(); v != 2 {
+		t.Errorf("Version after changed Reload = %d, want 2", v)
+	}
+	if v := l.Metadata().Version; v != 2 {
+		t.Errorf("Metadata().Version = %d, want 2", v)
+	}
+
+	mock.SetError(errors.New("doppler down"))
+	l.Reload(ctx)
+	if v := l.Version(); v != 2 {
+		t.Errorf("Version after failed Reload = %d, want 2", v)
+	}
+}
+
+func TestLoader_CurrentDuringReload(t *testing.T) {
+	type config struct {
+		A int `doppler:"A"`
+		B int `doppler:"B"`
+	}
+	mock := NewMockProvider(map[string]string{"A": "0", "B": "0"})
+	l := NewLoaderWithProvider[config](mock, nil)
+	ctx := context.Background()
+	if _, err := l.Load(ctx); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				// A and B are always written together, so a reader that
+				// sees them differ saw a partially updated config.
+				if cfg := l.Current(); cfg.A != cfg.B {
+					t.Errorf("Current() = %+v, a partially updated config", cfg)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 1; i <= 50; i++ {
+		v := strconv.Itoa(i)
+		mock.SetValues(map[string]string{"A": v, "B": v})
+		if _, err := l.Reload(ctx); err != nil {
+			t.Fatalf("Reload failed: %v", err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	if cfg := l.Current(); cfg.A != 50 {
+		t.Errorf("Current().A = %d, want 50", cfg.A)
+	}
+}
+
+func BenchmarkLoader_Current(b *testing.B) {
+	type config struct {
+		A int `doppler:"A"`
+	}
+	l := NewLoaderWithProvider[config](NewMockProvider(map[string]string{"A": "1"}), nil)
+	if _, err := l.Load(context.Background()); err != nil {
+		b.Fatalf("Load failed: %v", err)
+	}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if l.Current() == nil {
+				b.Fatal("Current() = nil")
+			}
+		}
+	})
+}
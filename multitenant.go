@@ -4,6 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"math/rand/v2"
+	"reflect"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -31,6 +35,16 @@ type MultiTenantLoader[E any, P any] interface {
 	// ReloadProjects reloads all project configurations and returns what changed.
 	ReloadProjects(ctx context.Context) (*ReloadDiff, error)
 
+	// ReconcileProjects makes the loaded projects match codes: new codes
+	// are loaded, loaded codes absent from codes are removed, and the rest
+	// are refreshed. Unlike ReloadProjects it updates the cache in place,
+	// so projects added by LoadProject while it runs are kept.
+	ReconcileProjects(ctx context.Context, codes []string) (*ReloadDiff, error)
+
+	// ReloadProject reloads a single project configuration and notifies
+	// OnProjectChange callbacks with a diff naming just that project.
+	ReloadProject(ctx context.Context, code string) (*ReloadDiff, error)
+
 	// Project returns a specific project config (from cache).
 	Project(code string) (*P, bool)
 
@@ -40,15 +54,35 @@ type MultiTenantLoader[E any, P any] interface {
 	// ProjectCodes returns a sorted list of loaded project codes.
 	ProjectCodes() []string
 
+	// ProjectMetadata returns where and when a loaded project's config
+	// came from, its key count and any unmarshal warnings. The bool is
+	// false if the project is not loaded.
+	ProjectMetadata(code string) (ConfigMetadata, bool)
+
+	// ProjectErrors returns the most recent load error of each project
+	// whose last load or reload failed. A project's entry is cleared when
+	// it next loads successfully or is removed.
+	ProjectErrors() map[string]error
+
 	// Env returns the current environment config.
 	Env() *E
 
+	// Snapshot returns the env config, all project configs and their
+	// metadata as one consistent view taken under a single lock.
+	Snapshot() MultiTenantSnapshot[E, P]
+
 	// OnEnvChange registers a callback for environment config changes.
 	OnEnvChange(fn func(old, new *E))
 
 	// OnProjectChange registers a callback for project config changes.
 	OnProjectChange(fn func(diff *ReloadDiff))
 
+	// OnProjectChangeFor registers a callback for changes to one project,
+	// called by the reloads that notify OnProjectChange when code was
+	// added (old is nil), removed (new is nil) or reloaded with a config
+	// that differs from the previous one.
+	OnProjectChangeFor(code string, fn func(old, new *P))
+
 	// Close releases resources.
 	Close() error
 }
@@ -60,6 +94,28 @@ type ReloadDiff struct {
 	Unchanged []string // Project codes that remained (may have updated)
 }
 
+// MultiTenantSnapshot is a consistent view of a MultiTenantLoader's state,
+// suitable for status pages.
+type MultiTenantSnapshot[E any, P any] struct {
+	// Generation increases by one every time the loader's state changes
+	// (LoadEnv, LoadProject, LoadAllProjects, ReloadProjects,
+	// ReconcileProjects). Two snapshots with the same generation hold the
+	// same configs.
+	Generation uint64
+
+	// Env is the environment config, or nil if LoadEnv has not succeeded.
+	Env *E
+
+	// EnvMetadata describes where and when Env was loaded.
+	EnvMetadata ConfigMetadata
+
+	// Projects holds the loaded project configs by code.
+	Projects map[string]*P
+
+	// ProjectMetadata describes where and when each project was loaded.
+	ProjectMetadata map[string]ConfigMetadata
+}
+
 // multiTenantLoader implements MultiTenantLoader.
 type multiTenantLoader[E any, P any] struct {
 	provider  Provider
@@ -69,10 +125,49 @@ type multiTenantLoader[E any, P any] struct {
 	mu          sync.RWMutex
 	envConfig   *E
 	projects    map[string]*P
-	projectKeys []string // Sorted list of project codes
+	projectKeys []string         // Sorted list of project codes
+	projectErrs map[string]error // Last load error per project code, cleared on success
+	envMeta     ConfigMetadata
+	projectMeta map[string]ConfigMetadata
+	generation  uint64
 
 	envCallbacks     []func(old, new *E)
 	projectCallbacks []func(diff *ReloadDiff)
+	codeCallbacks    map[string][]func(old, new *P)
+
+	inheritEnv   bool
+	envValues    map[string]string // Raw env values from the last LoadEnv
+	fetchTimeout time.Duration     // Per provider call; see WithTenantFetchTimeout
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// MultiTenantOption configures a MultiTenantLoader.
+type MultiTenantOption[E any, P any] func(*multiTenantLoader[E, P])
+
+// WithTenantInheritsEnv makes each tenant inherit the env-level values: the
+// raw values from the last LoadEnv are used as a base and the tenant's own
+// values are overlaid before unmarshaling into *P, so a tenant that omits a
+// key gets the shared value. Inheritance works on raw keys because E and P
+// are separate types. Call LoadEnv before loading projects; until then
+// tenants load with their own values only.
+func WithTenantInheritsEnv[E any, P any]() MultiTenantOption[E, P] {
+	return func(l *multiTenantLoader[E, P]) {
+		l.inheritEnv = true
+	}
+}
+
+// WithTenantFetchTimeout bounds every provider call the loader makes with a
+// timeout of d, so one slow tenant fails fast with
+// context.DeadlineExceeded instead of stalling LoadAllProjects or
+// ReloadProjects while the other tenants finish. The primary and fallback
+// each get their own d, so a hung primary still leaves time for the
+// fallback. A zero or negative d disables the timeout.
+func WithTenantFetchTimeout[E any, P any](d time.Duration) MultiTenantOption[E, P] {
+	return func(l *multiTenantLoader[E, P]) {
+		l.fetchTimeout = d
+	}
 }
 
 // MultiTenantBootstrap extends BootstrapConfig for multi-tenant scenarios.
@@ -81,13 +176,22 @@ type MultiTenantBootstrap struct {
 }
 
 // NewMultiTenantLoader creates a new multi-tenant loader.
-func NewMultiTenantLoader[E any, P any](bootstrap MultiTenantBootstrap) (MultiTenantLoader[E, P], error) {
+func NewMultiTenantLoader[E any, P any](bootstrap MultiTenantBootstrap, opts ...MultiTenantOption[E, P]) (MultiTenantLoader[E, P], error) {
 	l := &multiTenantLoader[E, P]{
-		bootstrap: bootstrap.BootstrapConfig,
-		projects:  make(map[string]*P),
+		bootstrap:   bootstrap.BootstrapConfig,
+		projects:    make(map[string]*P),
+		projectErrs: make(map[string]error),
+		projectMeta: make(map[string]ConfigMetadata),
+	}
+	for _, opt := range opts {
+		opt(l)
 	}
 
-	// Initialize primary provider (Doppler)
+	if bootstrap.Offline && !bootstrap.HasFallback() {
+		return nil, fmt.Errorf("offline mode requires a fallback: set DOPPLER_FALLBACK_PATH")
+	}
+
+	// Initialize primary provider (Doppler), skipped in offline mode
 	if bootstrap.IsEnabled() {
 		provider, err := NewDopplerProvider(bootstrap.Token, bootstrap.Project, bootstrap.Config,
 			WithProviderLogger(slog.Default()),
@@ -111,29 +215,39 @@ func NewMultiTenantLoader[E any, P any](bootstrap MultiTenantBootstrap) (MultiTe
 }
 
 // NewMultiTenantLoaderWithProvider creates a loader with custom providers.
-func NewMultiTenantLoaderWithProvider[E any, P any](provider, fallback Provider) MultiTenantLoader[E, P] {
-	return &multiTenantLoader[E, P]{
-		provider: provider,
-		fallback: fallback,
-		projects: make(map[string]*P),
+func NewMultiTenantLoaderWithProvider[E any, P any](provider, fallback Provider, opts ...MultiTenantOption[E, P]) MultiTenantLoader[E, P] {
+	l := &multiTenantLoader[E, P]{
+		provider:    provider,
+		fallback:    fallback,
+		projects:    make(map[string]*P),
+		projectErrs: make(map[string]error),
+		projectMeta: make(map[string]ConfigMetadata),
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 // LoadEnv implements MultiTenantLoader.LoadEnv.
 func (l *multiTenantLoader[E, P]) LoadEnv(ctx context.Context) (*E, error) {
-	values, err := l.fetchWithFallback(ctx, "", "")
+	values, source, role, err := l.fetchWithFallback(ctx, "", "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch env config: %w", err)
 	}
 
 	cfg := new(E)
-	if _, err := unmarshalConfig(values, cfg); err != nil {
+	warnings, err := unmarshalConfig(values, cfg)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse env config: %w", err)
 	}
 
 	l.mu.Lock()
 	old := l.envConfig
 	l.envConfig = cfg
+	l.envValues = values
+	l.envMeta = l.newMetadata(source, role, "", len(values), warnings)
+	l.generation++
 	callbacks := l.envCallbacks
 	l.mu.Unlock()
 
@@ -149,18 +263,48 @@ func (l *multiTenantLoader[E, P]) LoadEnv(ctx context.Context) (*E, error) {
 
 // LoadProject implements MultiTenantLoader.LoadProject.
 func (l *multiTenantLoader[E, P]) LoadProject(ctx context.Context, code string) (*P, error) {
-	values, err := l.fetchWithFallback(ctx, "", code)
+	return l.loadProjectChecked(ctx, code, nil)
+}
+
+// checkedProjectLoader is implemented by MultiTenantLoaders that can run a
+// check on a freshly parsed tenant config before storing it, like the one
+// NewMultiTenantLoader returns. projectLoader uses it to validate before
+// the new config is published.
+type checkedProjectLoader[P any] interface {
+	loadProjectChecked(ctx context.Context, code string, check func(*P) error) (*P, error)
+}
+
+// loadProjectChecked loads like LoadProject, but a non-nil error from check
+// rejects the new config before it is stored; the previous one is kept and
+// the error is recorded as the project's most recent failure.
+func (l *multiTenantLoader[E, P]) loadProjectChecked(ctx context.Context, code string, check func(*P) error) (*P, error) {
+	values, source, role, err := l.fetchProjectValues(ctx, code)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch project config for %s: %w", code, err)
+		err = fmt.Errorf("failed to fetch project config for %s: %w", code, err)
+		l.recordProjectError(code, err)
+		return nil, err
 	}
 
 	cfg := new(P)
-	if _, err := unmarshalConfig(values, cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse project config for %s: %w", code, err)
+	warnings, err := unmarshalConfig(values, cfg)
+	if err != nil {
+		err = fmt.Errorf("failed to parse project config for %s: %w", code, err)
+		l.recordProjectError(code, err)
+		return nil, err
+	}
+	if check != nil {
+		if err := check(cfg); err != nil {
+			err = fmt.Errorf("project config for %s rejected: %w", code, err)
+			l.recordProjectError(code, err)
+			return nil, err
+		}
 	}
 
 	l.mu.Lock()
 	l.projects[code] = cfg
+	l.projectMeta[code] = l.newMetadata(source, role, code, len(values), warnings)
+	l.generation++
+	delete(l.projectErrs, code)
 	l.updateProjectKeys()
 	l.mu.Unlock()
 
@@ -173,14 +317,17 @@ func (l *multiTenantLoader[E, P]) LoadAllProjects(ctx context.Context, projectCo
 	type codeResult struct {
 		code string
 		cfg  *P
+		meta ConfigMetadata
 	}
 
 	results, err := work.Map(ctx, projectCodes, func(ctx context.Context, code string) (codeResult, error) {
-		cfg, parseErr := l.fetchAndParse(ctx, code)
+		cfg, meta, parseErr := l.fetchAndParse(ctx, code)
 		if parseErr != nil {
-			return codeResult{}, fmt.Errorf("failed to load project %s: %w", code, parseErr)
+			err := fmt.Errorf("failed to load project %s: %w", code, parseErr)
+			l.recordProjectError(code, err)
+			return codeResult{}, err
 		}
-		return codeResult{code: code, cfg: cfg}, nil
+		return codeResult{code: code, cfg: cfg, meta: meta}, nil
 	}, work.Workers(5))
 	if err != nil {
 		return nil, err
@@ -191,7 +338,10 @@ func (l *multiTenantLoader[E, P]) LoadAllProjects(ctx context.Context, projectCo
 	for _, r := range results {
 		out[r.code] = r.cfg
 		l.projects[r.code] = r.cfg
+		l.projectMeta[r.code] = r.meta
+		delete(l.projectErrs, r.code)
 	}
+	l.generation++
 	l.updateProjectKeys()
 	l.mu.Unlock()
 
@@ -213,27 +363,31 @@ func (l *multiTenantLoader[E, P]) ReloadProjects(ctx context.Context) (*ReloadDi
 	type reloadResult struct {
 		code string
 		cfg  *P
+		meta ConfigMetadata
 	}
 
 	// Reload each project in parallel. work.Map returns partial results even on error.
 	results, mapErr := work.Map(ctx, codes, func(ctx context.Context, code string) (reloadResult, error) {
-		cfg, err := l.fetchAndParse(ctx, code)
+		cfg, meta, err := l.fetchAndParse(ctx, code)
 		if err != nil {
 			slog.Warn("failed to reload project config",
 				"project", code,
 				"error", err,
 			)
+			l.recordProjectError(code, err)
 			return reloadResult{}, err
 		}
-		return reloadResult{code: code, cfg: cfg}, nil
+		return reloadResult{code: code, cfg: cfg, meta: meta}, nil
 	}, work.Workers(5))
 
 	// Collect successful reloads (work.Map returns results for all items, including failed ones).
 	newProjects := make(map[string]*P, len(results))
+	newMeta := make(map[string]ConfigMetadata, len(results))
 	var reloadErrors []string
 	for i, r := range results {
 		if r.cfg != nil {
 			newProjects[r.code] = r.cfg
+			newMeta[r.code] = r.meta
 		} else if mapErr != nil {
 			reloadErrors = append(reloadErrors, codes[i])
 		}
@@ -272,18 +426,197 @@ func (l *multiTenantLoader[E, P]) ReloadProjects(ctx context.Context) (*ReloadDi
 	sort.Strings(diff.Unchanged)
 
 	// Apply changes
+	updated := maps.Clone(newProjects)
 	l.mu.Lock()
+	previous := l.projects
 	l.projects = newProjects
+	l.projectMeta = newMeta
+	l.generation++
+	for code := range newProjects {
+		delete(l.projectErrs, code)
+	}
 	l.updateProjectKeys()
 	callbacks := l.projectCallbacks
+	codeCallbacks := maps.Clone(l.codeCallbacks)
 	l.mu.Unlock()
 
-	// Notify callbacks
+	notifyProjectChange(callbacks, codeCallbacks, diff, previous, updated)
+
+	return diff, nil
+}
+
+// ReconcileProjects implements MultiTenantLoader.ReconcileProjects.
+// Projects are fetched in parallel with bounded concurrency (5 workers).
+// Only projects loaded when the reconcile started are candidates for
+// removal; the recorded load errors of projects absent from codes are
+// cleared, whether or not they are loaded. A project that fails to refresh keeps its previous config and a
+// new one that fails to load is not added; the diff is still applied and
+// returned together with an error naming the failed projects.
+func (l *multiTenantLoader[E, P]) ReconcileProjects(ctx context.Context, codes []string) (*ReloadDiff, error) {
+	desired := make(map[string]bool, len(codes))
+	unique := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if !desired[code] {
+			desired[code] = true
+			unique = append(unique, code)
+		}
+	}
+
+	// Stale codes include tenants that are only remembered for a failed
+	// load, so removing them also clears their error.
+	l.mu.RLock()
+	var stale []string
+	for code := range l.projects {
+		if !desired[code] {
+			stale = append(stale, code)
+		}
+	}
+	for code := range l.projectErrs {
+		if _, loaded := l.projects[code]; !loaded && !desired[code] {
+			stale = append(stale, code)
+		}
+	}
+	l.mu.RUnlock()
+
+	type reloadResult struct {
+		code string
+		cfg  *P
+		meta ConfigMetadata
+	}
+
+	results, _ := work.Map(ctx, unique, func(ctx context.Context, code string) (reloadResult, error) {
+		cfg, meta, err := l.fetchAndParse(ctx, code)
+		if err != nil {
+			slog.Warn("failed to reconcile project config",
+				"project", code,
+				"error", err,
+			)
+			l.recordProjectError(code, err)
+			return reloadResult{}, err
+		}
+		return reloadResult{code: code, cfg: cfg, meta: meta}, nil
+	}, work.Workers(5))
+
+	diff := &ReloadDiff{
+		Added:     make([]string, 0),
+		Removed:   make([]string, 0),
+		Unchanged: make([]string, 0),
+	}
+	var failed []string
+	previous := make(map[string]*P)
+	updated := make(map[string]*P)
+
+	l.mu.Lock()
+	for _, code := range stale {
+		delete(l.projectMeta, code)
+		delete(l.projectErrs, code)
+		if cfg, ok := l.projects[code]; ok {
+			previous[code] = cfg
+			delete(l.projects, code)
+			diff.Removed = append(diff.Removed, code)
+		}
+	}
+	for i, code := range unique {
+		old, existed := l.projects[code]
+		if existed {
+			previous[code] = old
+			updated[code] = old
+		}
+		if i < len(results) && results[i].cfg != nil {
+			updated[code] = results[i].cfg
+			l.projects[code] = results[i].cfg
+			l.projectMeta[code] = results[i].meta
+			delete(l.projectErrs, code)
+		} else {
+			failed = append(failed, code)
+			if !existed {
+				continue
+			}
+		}
+		if existed {
+			diff.Unchanged = append(diff.Unchanged, code)
+		} else {
+			diff.Added = append(diff.Added, code)
+		}
+	}
+	l.generation++
+	l.updateProjectKeys()
+	callbacks := l.projectCallbacks
+	codeCallbacks := maps.Clone(l.codeCallbacks)
+	l.mu.Unlock()
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Unchanged)
+
+	notifyProjectChange(callbacks, codeCallbacks, diff, previous, updated)
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return diff, fmt.Errorf("failed to reconcile %d project(s): %v", len(failed), failed)
+	}
+	return diff, nil
+}
+
+// ReloadProject implements MultiTenantLoader.ReloadProject.
+func (l *multiTenantLoader[E, P]) ReloadProject(ctx context.Context, code string) (*ReloadDiff, error) {
+	old, existed := l.Project(code)
+
+	cfg, err := l.LoadProject(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &ReloadDiff{
+		Added:     make([]string, 0),
+		Removed:   make([]string, 0),
+		Unchanged: make([]string, 0),
+	}
+	if existed {
+		diff.Unchanged = append(diff.Unchanged, code)
+	} else {
+		diff.Added = append(diff.Added, code)
+	}
+
+	l.mu.RLock()
+	callbacks := l.projectCallbacks
+	codeCallbacks := maps.Clone(l.codeCallbacks)
+	l.mu.RUnlock()
+
+	notifyProjectChange(callbacks, codeCallbacks, diff,
+		map[string]*P{code: old}, map[string]*P{code: cfg})
+
+	return diff, nil
+}
+
+// notifyProjectChange calls the OnProjectChange callbacks with diff, then
+// the OnProjectChangeFor callbacks of each project that diff lists as added
+// or removed, or as unchanged but with a config that differs between
+// previous and updated.
+func notifyProjectChange[P any](callbacks []func(diff *ReloadDiff), codeCallbacks map[string][]func(old, new *P), diff *ReloadDiff, previous, updated map[string]*P) {
 	for _, cb := range callbacks {
 		cb(diff)
 	}
+	if len(codeCallbacks) == 0 {
+		return
+	}
 
-	return diff, nil
+	notify := func(code string) {
+		for _, fn := range codeCallbacks[code] {
+			fn(previous[code], updated[code])
+		}
+	}
+	for _, code := range diff.Added {
+		notify(code)
+	}
+	for _, code := range diff.Removed {
+		notify(code)
+	}
+	for _, code := range diff.Unchanged {
+		if !reflect.DeepEqual(previous[code], updated[code]) {
+			notify(code)
+		}
+	}
 }
 
 // Project implements MultiTenantLoader.Project.
@@ -314,6 +647,14 @@ func (l *multiTenantLoader[E, P]) ProjectCodes() []string {
 	return codes
 }
 
+// ProjectMetadata implements MultiTenantLoader.ProjectMetadata.
+func (l *multiTenantLoader[E, P]) ProjectMetadata(code string) (ConfigMetadata, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	meta, ok := l.projectMeta[code]
+	return meta, ok
+}
+
 // Env implements MultiTenantLoader.Env.
 func (l *multiTenantLoader[E, P]) Env() *E {
 	l.mu.RLock()
@@ -321,6 +662,27 @@ func (l *multiTenantLoader[E, P]) Env() *E {
 	return l.envConfig
 }
 
+// Snapshot implements MultiTenantLoader.Snapshot.
+func (l *multiTenantLoader[E, P]) Snapshot() MultiTenantSnapshot[E, P] {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	snap := MultiTenantSnapshot[E, P]{
+		Generation:      l.generation,
+		Env:             l.envConfig,
+		EnvMetadata:     l.envMeta,
+		Projects:        make(map[string]*P, len(l.projects)),
+		ProjectMetadata: make(map[string]ConfigMetadata, len(l.projectMeta)),
+	}
+	for code, cfg := range l.projects {
+		snap.Projects[code] = cfg
+	}
+	for code, meta := range l.projectMeta {
+		snap.ProjectMetadata[code] = meta
+	}
+	return snap
+}
+
 // OnEnvChange implements MultiTenantLoader.OnEnvChange.
 func (l *multiTenantLoader[E, P]) OnEnvChange(fn func(old, new *E)) {
 	l.mu.Lock()
@@ -335,8 +697,26 @@ func (l *multiTenantLoader[E, P]) OnProjectChange(fn func(diff *ReloadDiff)) {
 	l.projectCallbacks = append(l.projectCallbacks, fn)
 }
 
+// OnProjectChangeFor implements MultiTenantLoader.OnProjectChangeFor.
+func (l *multiTenantLoader[E, P]) OnProjectChangeFor(code string, fn func(old, new *P)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.codeCallbacks == nil {
+		l.codeCallbacks = make(map[string][]func(old, new *P))
+	}
+	l.codeCallbacks[code] = append(l.codeCallbacks[code], fn)
+}
+
 // Close implements MultiTenantLoader.Close.
+// The providers are closed on the first call only.
 func (l *multiTenantLoader[E, P]) Close() error {
+	l.closeOnce.Do(func() {
+		l.closeErr = l.closeProviders()
+	})
+	return l.closeErr
+}
+
+func (l *multiTenantLoader[E, P]) closeProviders() error {
 	var errs []error
 	if l.provider != nil {
 		if err := l.provider.Close(); err != nil {
@@ -354,41 +734,112 @@ func (l *multiTenantLoader[E, P]) Close() error {
 	return nil
 }
 
-func (l *multiTenantLoader[E, P]) fetchWithFallback(ctx context.Context, project, config string) (map[string]string, error) {
+// fetchWithFallback fetches from the primary provider, falling back on
+// error, and returns the values with the name and role of the provider that
+// served them.
+func (l *multiTenantLoader[E, P]) fetchWithFallback(ctx context.Context, project, config string) (map[string]string, string, ProviderRole, error) {
 	var values map[string]string
 	var err error
 
 	// Try primary provider first
 	if l.provider != nil {
-		values, err = l.provider.FetchProject(ctx, project, config)
+		values, err = l.fetchProject(ctx, l.provider, project, config)
 		if err == nil {
-			return values, nil
+			return values, l.provider.Name(), RolePrimary, nil
 		}
 	}
 
 	// Fall back if primary failed
 	if l.fallback != nil {
-		values, err = l.fallback.FetchProject(ctx, project, config)
+		values, err = l.fetchProject(ctx, l.fallback, project, config)
 		if err == nil {
-			return values, nil
+			return values, l.fallback.Name(), RoleFallback, nil
 		}
 	}
 
-	return nil, err
+	return nil, "", "", err
+}
+
+// fetchProject calls p.FetchProject, bounded by WithTenantFetchTimeout.
+func (l *multiTenantLoader[E, P]) fetchProject(ctx context.Context, p Provider, project, config string) (map[string]string, error) {
+	if l.fetchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.fetchTimeout)
+		defer cancel()
+	}
+	return p.FetchProject(ctx, project, config)
+}
+
+// fetchProjectValues fetches a tenant's raw values, layered over the env
+// values when WithTenantInheritsEnv is set.
+func (l *multiTenantLoader[E, P]) fetchProjectValues(ctx context.Context, code string) (map[string]string, string, ProviderRole, error) {
+	values, source, role, err := l.fetchWithFallback(ctx, "", code)
+	if err != nil || !l.inheritEnv {
+		return values, source, role, err
+	}
+
+	l.mu.RLock()
+	envValues := l.envValues
+	l.mu.RUnlock()
+
+	merged := make(map[string]string, len(envValues)+len(values))
+	for k, v := range envValues {
+		merged[k] = v
+	}
+	for k, v := range values {
+		merged[k] = v
+	}
+	return merged, source, role, nil
 }
 
-func (l *multiTenantLoader[E, P]) fetchAndParse(ctx context.Context, code string) (*P, error) {
-	values, err := l.fetchWithFallback(ctx, "", code)
+func (l *multiTenantLoader[E, P]) fetchAndParse(ctx context.Context, code string) (*P, ConfigMetadata, error) {
+	values, source, role, err := l.fetchProjectValues(ctx, code)
 	if err != nil {
-		return nil, err
+		return nil, ConfigMetadata{}, err
 	}
 
 	cfg := new(P)
-	if _, err := unmarshalConfig(values, cfg); err != nil {
-		return nil, err
+	warnings, err := unmarshalConfig(values, cfg)
+	if err != nil {
+		return nil, ConfigMetadata{}, err
 	}
 
-	return cfg, nil
+	return cfg, l.newMetadata(source, role, code, len(values), warnings), nil
+}
+
+// newMetadata builds the ConfigMetadata recorded for a successful load.
+func (l *multiTenantLoader[E, P]) newMetadata(source string, role ProviderRole, config string, keyCount int, warnings []string) ConfigMetadata {
+	if config == "" {
+		config = l.bootstrap.Config
+	}
+	return ConfigMetadata{
+		Source:          source,
+		Role:            role,
+		LoadedAt:        time.Now(),
+		Project:         l.bootstrap.Project,
+		Config:          config,
+		EffectiveConfig: config,
+		KeyCount:        keyCount,
+		Warnings:        warnings,
+	}
+}
+
+// recordProjectError remembers the most recent load failure for a project.
+func (l *multiTenantLoader[E, P]) recordProjectError(code string, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.projectErrs[code] = err
+}
+
+// ProjectErrors returns a copy of the per-project load failures.
+func (l *multiTenantLoader[E, P]) ProjectErrors() map[string]error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	errs := make(map[string]error, len(l.projectErrs))
+	for code, err := range l.projectErrs {
+		errs[code] = err
+	}
+	return errs
 }
 
 func (l *multiTenantLoader[E, P]) updateProjectKeys() {
@@ -400,11 +851,34 @@ func (l *multiTenantLoader[E, P]) updateProjectKeys() {
 	l.projectKeys = keys
 }
 
+// ReloadStrategy selects how a MultiTenantWatcher refreshes tenants.
+type ReloadStrategy int
+
+const (
+	// ReloadAllAtOnce reloads the env config and every tenant with
+	// ReloadProjects on each tick. This is the default.
+	ReloadAllAtOnce ReloadStrategy = iota
+
+	// ReloadStaggered spreads tenant reloads across each interval instead
+	// of bursting: the interval is divided into one slot per tenant and
+	// each tenant is reloaded with ReloadProject at a random point in its
+	// slot, so every tenant is refreshed about once per interval. The env
+	// config is reloaded at the end of each interval. A tenant that fails
+	// to reload keeps its previous config.
+	ReloadStaggered
+)
+
 // MultiTenantWatcher watches both env and project configs.
 type MultiTenantWatcher[E any, P any] struct {
 	loader   MultiTenantLoader[E, P]
 	interval time.Duration
 	logger   *slog.Logger
+	strategy ReloadStrategy
+
+	clock Clock
+
+	// jitter returns a uniform random duration in [0, d) outside tests.
+	jitter func(d time.Duration) time.Duration
 
 	mu      sync.Mutex
 	running bool
@@ -418,6 +892,13 @@ func NewMultiTenantWatcher[E any, P any](loader MultiTenantLoader[E, P], interva
 		loader:   loader,
 		interval: interval,
 		logger:   slog.Default(),
+		clock:    realClock{},
+		jitter: func(d time.Duration) time.Duration {
+			if d <= 0 {
+				return 0
+			}
+			return rand.N(d)
+		},
 	}
 }
 
@@ -427,6 +908,21 @@ func (w *MultiTenantWatcher[E, P]) WithLogger(logger *slog.Logger) *MultiTenantW
 	return w
 }
 
+// WithClock sets the clock that drives polling. The default is the system
+// clock; pass a FakeClock in tests.
+func (w *MultiTenantWatcher[E, P]) WithClock(clock Clock) *MultiTenantWatcher[E, P] {
+	w.clock = clock
+	return w
+}
+
+// WithReloadStrategy sets how tenants are refreshed; the default is
+// ReloadAllAtOnce. Use ReloadStaggered with many tenants to avoid a burst
+// of fetches every interval.
+func (w *MultiTenantWatcher[E, P]) WithReloadStrategy(strategy ReloadStrategy) *MultiTenantWatcher[E, P] {
+	w.strategy = strategy
+	return w
+}
+
 // Start begins watching for changes.
 func (w *MultiTenantWatcher[E, P]) Start(ctx context.Context) error {
 	w.mu.Lock()
@@ -463,7 +959,12 @@ func (w *MultiTenantWatcher[E, P]) run(ctx context.Context) {
 		w.mu.Unlock()
 	}()
 
-	ticker := time.NewTicker(w.interval)
+	if w.strategy == ReloadStaggered {
+		w.runStaggered(ctx)
+		return
+	}
+
+	ticker := w.clock.NewTicker(w.interval)
 	defer ticker.Stop()
 
 	for {
@@ -474,11 +975,8 @@ func (w *MultiTenantWatcher[E, P]) run(ctx context.Context) {
 		case <-w.stopCh:
 			w.logger.Info("multi-tenant watcher stopping: stop requested")
 			return
-		case <-ticker.C:
-			// Reload env config
-			if _, err := w.loader.LoadEnv(ctx); err != nil {
-				w.logger.Warn("failed to reload env config", "error", err)
-			}
+		case <-ticker.C():
+			w.reloadEnv(ctx)
 			// Reload project configs
 			if _, err := w.loader.ReloadProjects(ctx); err != nil {
 				w.logger.Warn("failed to reload project configs", "error", err)
@@ -486,3 +984,211 @@ func (w *MultiTenantWatcher[E, P]) run(ctx context.Context) {
 		}
 	}
 }
+
+// runStaggered implements ReloadStaggered. Each round lasts one interval;
+// the tenant list is re-read at the start of every round.
+func (w *MultiTenantWatcher[E, P]) runStaggered(ctx context.Context) {
+	for {
+		codes := w.loader.ProjectCodes()
+
+		var elapsed time.Duration
+		if len(codes) > 0 {
+			slot := w.interval / time.Duration(len(codes))
+			for i, code := range codes {
+				due := time.Duration(i)*slot + w.jitter(slot)
+				if !w.wait(ctx, due-elapsed) {
+					return
+				}
+				elapsed = due
+				if _, err := w.loader.ReloadProject(ctx, code); err != nil {
+					w.logger.Warn("failed to reload project config", "project", code, "error", err)
+				}
+			}
+		}
+
+		if !w.wait(ctx, w.interval-elapsed) {
+			return
+		}
+		w.reloadEnv(ctx)
+	}
+}
+
+// wait blocks for d and reports false if the watcher should stop instead.
+func (w *MultiTenantWatcher[E, P]) wait(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := w.clock.NewTicker(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		w.logger.Info("multi-tenant watcher stopping: context cancelled")
+		return false
+	case <-w.stopCh:
+		w.logger.Info("multi-tenant watcher stopping: stop requested")
+		return false
+	case <-timer.C():
+		return true
+	}
+}
+
+func (w *MultiTenantWatcher[E, P]) reloadEnv(ctx context.Context) {
+	if _, err := w.loader.LoadEnv(ctx); err != nil {
+		w.logger.Warn("failed to reload env config", "error", err)
+	}
+}
+
+// WatchProject polls a single tenant every interval with LoadProject and
+// calls callback with the old and new config whenever it changes, for a
+// worker that only needs its own tenant. It runs a Watcher, so opts such as
+// WithMaxFailures, WithWatchLogger and WithValidateReload behave as they do
+// for Watch; an interval set with WithWatchInterval overrides interval. It returns a stop
+// function that should be called when done.
+func WatchProject[E any, P any](ctx context.Context, loader MultiTenantLoader[E, P], code string, interval time.Duration, callback func(old, new *P), opts ...WatcherOption[P]) (stop func()) {
+	pl := &projectLoader[E, P]{loader: loader, code: code}
+	pl.OnChange(callback)
+	opts = append([]WatcherOption[P]{WithWatchInterval[P](interval)}, opts...)
+	return Watch[P](ctx, pl, opts...)
+}
+
+// projectLoader adapts one tenant of a MultiTenantLoader to Loader[P] so it
+// can be driven by a Watcher. OnChange callbacks fire only when a reload
+// returns a config that differs from the previous one. LoadAndValidate,
+// Warmup and WithValidateReload checks run before the new config is stored
+// when the MultiTenantLoader implements checkedProjectLoader, as the
+// package's own does; otherwise they run after it.
+type projectLoader[E any, P any] struct {
+	loader MultiTenantLoader[E, P]
+	code   string
+
+	mu        sync.RWMutex
+	callbacks []func(old, new *P)
+	lastErr   error
+	version   uint64
+}
+
+func (l *projectLoader[E, P]) Load(ctx context.Context) (*P, error) {
+	return l.load(ctx, nil)
+}
+
+// load loads the project, rejecting it if check fails; see projectLoader.
+func (l *projectLoader[E, P]) load(ctx context.Context, check func(*P) error) (*P, error) {
+	old, _ := l.loader.Project(l.code)
+	var cfg *P
+	var err error
+	if cl, ok := l.loader.(checkedProjectLoader[P]); ok {
+		cfg, err = cl.loadProjectChecked(ctx, l.code, check)
+	} else {
+		cfg, err = l.loader.LoadProject(ctx, l.code)
+		if err == nil && check != nil {
+			if cerr := check(cfg); cerr != nil {
+				cfg, err = nil, fmt.Errorf("project config for %s rejected after it was applied: %w", l.code, cerr)
+			}
+		}
+	}
+
+	changed := err == nil && (old == nil || !reflect.DeepEqual(old, cfg))
+	l.mu.Lock()
+	l.lastErr = err
+	if changed {
+		l.version++
+	}
+	callbacks := l.callbacks
+	l.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	if old != nil && changed {
+		for _, cb := range callbacks {
+			cb(old, cfg)
+		}
+	}
+	return cfg, nil
+}
+
+func (l *projectLoader[E, P]) Reload(ctx context.Context) (*P, error) {
+	return l.Load(ctx)
+}
+
+// LoadAndValidate loads the project and checks it with Validate before it
+// is stored, so an invalid config never becomes current.
+func (l *projectLoader[E, P]) LoadAndValidate(ctx context.Context) (*P, error) {
+	return l.load(ctx, func(cfg *P) error { return Validate(cfg) })
+}
+
+// Warmup loads and validates the project like LoadAndValidate.
+func (l *projectLoader[E, P]) Warmup(ctx context.Context) error {
+	_, err := l.LoadAndValidate(ctx)
+	return err
+}
+
+// reloadChecked reloads the project, rejecting it if check fails, so a
+// Watcher's WithValidateReload applies to WatchProject.
+func (l *projectLoader[E, P]) reloadChecked(ctx context.Context, check func(*P) error) (*P, error) {
+	return l.load(ctx, check)
+}
+
+func (l *projectLoader[E, P]) Current() *P {
+	cfg, _ := l.loader.Project(l.code)
+	return cfg
+}
+
+func (l *projectLoader[E, P]) OnChange(fn func(old, new *P)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.callbacks = append(l.callbacks, fn)
+}
+
+func (l *projectLoader[E, P]) Metadata() ConfigMetadata {
+	meta, _ := l.loader.ProjectMetadata(l.code)
+	meta.Version = l.Version()
+	return meta
+}
+
+// Version counts the loads through this loader that changed the project.
+func (l *projectLoader[E, P]) Version() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.version
+}
+
+func (l *projectLoader[E, P]) Warnings() []string {
+	return slices.Clone(l.Metadata().Warnings)
+}
+
+func (l *projectLoader[E, P]) Stale() bool {
+	return l.LastError() != nil && l.Current() != nil
+}
+
+func (l *projectLoader[E, P]) LastError() error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.lastErr
+}
+
+// Providers returns nils: MultiTenantLoader does not expose its providers.
+func (l *projectLoader[E, P]) Providers() (primary, fallback Provider) {
+	return nil, nil
+}
+
+// DebugString summarizes the project's metadata; see Loader.DebugString.
+func (l *projectLoader[E, P]) DebugString() string {
+	return debugString(l.Metadata(), nil, l.Stale())
+}
+
+// VerifyKeys always fails: a project loader has no providers of its own.
+func (l *projectLoader[E, P]) VerifyKeys(ctx context.Context, keys []string) ([]string, error) {
+	return nil, errNoProviders
+}
+
+// Close is a no-op; the shared MultiTenantLoader is left open.
+func (l *projectLoader[E, P]) Close() error {
+	return nil
+}
+
+// invalidateCaches drops the provider caches, so the next reload reads
+// fresh values. MultiTenantWebhookHandler calls it before reloading.
+func (l *multiTenantLoader[E, P]) invalidateCaches() {
+	invalidateProviderCaches(l.provider, l.fallback)
+}
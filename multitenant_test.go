@@ -2,8 +2,15 @@ package dopplerconfig
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type MTEnvConfig struct {
@@ -76,6 +83,50 @@ func TestMultiTenantLoader_LoadProject(t *testing.T) {
 	}
 }
 
+func TestMultiTenantLoader_ProjectMetadata(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockProvider(nil).WithName("tenants")
+	mock.SetProjectValues("", "proj-a", map[string]string{"PROJECT_NAME": "A", "MAX_CONNS": "25"})
+	mock.SetProjectValues("", "proj-b", map[string]string{"PROJECT_NAME": "B"})
+
+	loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](mock, nil)
+	if _, ok := loader.ProjectMetadata("proj-a"); ok {
+		t.Error("ProjectMetadata(proj-a) found before loading")
+	}
+
+	if _, err := loader.LoadProject(ctx, "proj-a"); err != nil {
+		t.Fatalf("LoadProject failed: %v", err)
+	}
+	if _, err := loader.LoadAllProjects(ctx, []string{"proj-b"}); err != nil {
+		t.Fatalf("LoadAllProjects failed: %v", err)
+	}
+
+	for code, keys := range map[string]int{"proj-a": 2, "proj-b": 1} {
+		meta, ok := loader.ProjectMetadata(code)
+		if !ok {
+			t.Fatalf("ProjectMetadata(%s) not found after loading", code)
+		}
+		if meta.Source != "tenants" || meta.Config != code || meta.KeyCount != keys || meta.LoadedAt.IsZero() {
+			t.Errorf("ProjectMetadata(%s) = %+v, want source tenants, config %s, %d keys and a load time", code, meta, code, keys)
+		}
+	}
+}
+
+func TestMultiTenantLoader_ProjectWarnings(t *testing.T) {
+	mock := NewMockProvider(nil)
+	mock.SetProjectValues("", "proj-a", map[string]string{"MAX_CONNS": "lots"})
+
+	loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](mock, nil)
+	if _, err := loader.LoadAllProjects(context.Background(), []string{"proj-a"}); err != nil {
+		t.Fatalf("LoadAllProjects failed: %v", err)
+	}
+
+	meta, _ := loader.ProjectMetadata("proj-a")
+	if len(meta.Warnings) != 1 || !strings.Contains(meta.Warnings[0], "MaxConns") {
+		t.Errorf("ProjectMetadata(proj-a).Warnings = %v, want one warning for MaxConns", meta.Warnings)
+	}
+}
+
 func TestMultiTenantLoader_ProjectCodes(t *testing.T) {
 	mock := NewMockProvider(nil)
 	mock.SetProjectValues("", "b-proj", map[string]string{"PROJECT_NAME": "B"})
@@ -181,6 +232,56 @@ func TestMultiTenantLoader_OnProjectChange(t *testing.T) {
 	}
 }
 
+func TestMultiTenantLoader_OnProjectChangeFor(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockProvider(nil)
+	mock.SetProjectValues("", "acme", map[string]string{"PROJECT_NAME": "Acme"})
+	mock.SetProjectValues("", "globex", map[string]string{"PROJECT_NAME": "Globex"})
+
+	loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](mock, nil)
+	if _, err := loader.LoadAllProjects(ctx, []string{"acme", "globex"}); err != nil {
+		t.Fatalf("LoadAllProjects failed: %v", err)
+	}
+
+	type change struct{ old, new string }
+	var changes []change
+	loader.OnProjectChangeFor("acme", func(old, new *MTProjectConfig) {
+		var c change
+		if old != nil {
+			c.old = old.Name
+		}
+		if new != nil {
+			c.new = new.Name
+		}
+		changes = append(changes, c)
+	})
+
+	// Only globex changes: the acme callback stays quiet.
+	mock.SetProjectValues("", "globex", map[string]string{"PROJECT_NAME": "Globex Corp"})
+	if _, err := loader.ReloadProjects(ctx); err != nil {
+		t.Fatalf("ReloadProjects failed: %v", err)
+	}
+	if _, err := loader.ReloadProject(ctx, "globex"); err != nil {
+		t.Fatalf("ReloadProject failed: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("acme callback fired for other tenants: %v", changes)
+	}
+
+	mock.SetProjectValues("", "acme", map[string]string{"PROJECT_NAME": "Acme Inc"})
+	if _, err := loader.ReloadProjects(ctx); err != nil {
+		t.Fatalf("ReloadProjects failed: %v", err)
+	}
+	if _, err := loader.ReconcileProjects(ctx, []string{"globex"}); err != nil {
+		t.Fatalf("ReconcileProjects failed: %v", err)
+	}
+
+	want := []change{{"Acme", "Acme Inc"}, {"Acme Inc", ""}}
+	if fmt.Sprint(changes) != fmt.Sprint(want) {
+		t.Errorf("acme changes = %v, want %v", changes, want)
+	}
+}
+
 func TestMultiTenantLoader_Close(t *testing.T) {
 	mock := NewMockProvider(map[string]string{})
 	fallback := NewMockProvider(map[string]string{})
@@ -241,3 +342,533 @@ func TestMultiTenantLoader_OnEnvChange_NoCallbackOnFirstLoad(t *testing.T) {
 		t.Error("OnEnvChange should not fire on first load")
 	}
 }
+
+func TestNewMultiTenantLoader_Offline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fallback.json")
+	if err := os.WriteFile(path, []byte(`{"REGION": "offline-region"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	bootstrap := MultiTenantBootstrap{BootstrapConfig: TestBootstrap()}
+	bootstrap.FallbackPath = path
+	bootstrap.Offline = true
+
+	loader, err := NewMultiTenantLoader[MTEnvConfig, MTProjectConfig](bootstrap)
+	if err != nil {
+		t.Fatalf("NewMultiTenantLoader failed: %v", err)
+	}
+	if loader.(*multiTenantLoader[MTEnvConfig, MTProjectConfig]).provider != nil {
+		t.Error("offline loader should not create a doppler provider")
+	}
+
+	env, err := loader.LoadEnv(context.Background())
+	if err != nil {
+		t.Fatalf("LoadEnv failed: %v", err)
+	}
+	if env.Region != "offline-region" {
+		t.Errorf("Region = %q, want %q", env.Region, "offline-region")
+	}
+}
+
+func TestNewMultiTenantLoader_OfflineWithoutFallback(t *testing.T) {
+	bootstrap := MultiTenantBootstrap{BootstrapConfig: TestBootstrap()}
+	bootstrap.Offline = true
+
+	if _, err := NewMultiTenantLoader[MTEnvConfig, MTProjectConfig](bootstrap); err == nil {
+		t.Error("NewMultiTenantLoader should fail when offline mode has no fallback")
+	}
+}
+
+func TestMultiTenantLoader_MockStrictProjects(t *testing.T) {
+	newMock := func(strict bool) *MockProvider {
+		mock := NewMockProvider(map[string]string{"PROJECT_NAME": "shared-default"})
+		mock.SetProjectValues("", "known", map[string]string{"PROJECT_NAME": "Known"})
+		mock.StrictProjects = strict
+		return mock
+	}
+
+	t.Run("lenient", func(t *testing.T) {
+		loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](newMock(false), nil)
+		cfg, err := loader.LoadProject(context.Background(), "missing")
+		if err != nil {
+			t.Fatalf("LoadProject failed: %v", err)
+		}
+		if cfg.Name != "shared-default" {
+			t.Errorf("Name = %q, want fallback to default values", cfg.Name)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](newMock(true), nil)
+
+		cfg, err := loader.LoadProject(context.Background(), "known")
+		if err != nil {
+			t.Fatalf("LoadProject(known) failed: %v", err)
+		}
+		if cfg.Name != "Known" {
+			t.Errorf("Name = %q, want %q", cfg.Name, "Known")
+		}
+
+		_, err = loader.LoadProject(context.Background(), "missing")
+		if err == nil {
+			t.Fatal("LoadProject(missing) should fail in strict mode")
+		}
+		if de, ok := IsDopplerError(err); !ok || de.StatusCode != 404 {
+			t.Errorf("error = %v, want 404 DopplerError", err)
+		}
+		if _, ok := loader.Project("missing"); ok {
+			t.Error("missing tenant should not be cached")
+		}
+	})
+}
+
+func TestMultiTenantLoader_TenantInheritsEnv(t *testing.T) {
+	newMock := func() *MockProvider {
+		mock := NewMockProvider(map[string]string{"REGION": "eu-west-1", "MAX_CONNS": "50"})
+		mock.SetProjectValues("", "acme", map[string]string{"PROJECT_NAME": "Acme"})
+		mock.SetProjectValues("", "globex", map[string]string{"PROJECT_NAME": "Globex", "MAX_CONNS": "5"})
+		return mock
+	}
+	ctx := context.Background()
+
+	loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](newMock(), nil,
+		WithTenantInheritsEnv[MTEnvConfig, MTProjectConfig]())
+	if _, err := loader.LoadEnv(ctx); err != nil {
+		t.Fatalf("LoadEnv failed: %v", err)
+	}
+	if _, err := loader.LoadAllProjects(ctx, []string{"acme", "globex"}); err != nil {
+		t.Fatalf("LoadAllProjects failed: %v", err)
+	}
+
+	acme, _ := loader.Project("acme")
+	if acme.MaxConns != 50 {
+		t.Errorf("acme MaxConns = %d, want 50 inherited from env", acme.MaxConns)
+	}
+	globex, _ := loader.Project("globex")
+	if globex.MaxConns != 5 {
+		t.Errorf("globex MaxConns = %d, want its own override 5", globex.MaxConns)
+	}
+
+	// LoadProject takes the same path.
+	acme, err := loader.LoadProject(ctx, "acme")
+	if err != nil {
+		t.Fatalf("LoadProject failed: %v", err)
+	}
+	if acme.MaxConns != 50 {
+		t.Errorf("LoadProject acme MaxConns = %d, want 50", acme.MaxConns)
+	}
+
+	// Without the option tenants are independent.
+	plain := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](newMock(), nil)
+	if _, err := plain.LoadEnv(ctx); err != nil {
+		t.Fatalf("LoadEnv failed: %v", err)
+	}
+	acme, _ = plain.LoadProject(ctx, "acme")
+	if acme.MaxConns != 10 {
+		t.Errorf("acme MaxConns without inheritance = %d, want default 10", acme.MaxConns)
+	}
+}
+
+func TestMultiTenantLoader_Snapshot(t *testing.T) {
+	mock := NewMockProvider(map[string]string{"REGION": "eu-west-1"})
+	mock.SetProjectValues("", "acme", map[string]string{"PROJECT_NAME": "Acme"})
+	loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](mock, nil)
+	ctx := context.Background()
+
+	empty := loader.Snapshot()
+	if empty.Generation != 0 || empty.Env != nil || len(empty.Projects) != 0 {
+		t.Errorf("initial snapshot = %+v, want empty generation 0", empty)
+	}
+
+	if _, err := loader.LoadEnv(ctx); err != nil {
+		t.Fatalf("LoadEnv failed: %v", err)
+	}
+	if _, err := loader.LoadAllProjects(ctx, []string{"acme"}); err != nil {
+		t.Fatalf("LoadAllProjects failed: %v", err)
+	}
+
+	before := loader.Snapshot()
+	if before.Env == nil || before.Env.Region != "eu-west-1" {
+		t.Errorf("snapshot Env = %+v, want Region eu-west-1", before.Env)
+	}
+	if before.EnvMetadata.Source != mock.Name() || before.EnvMetadata.LoadedAt.IsZero() {
+		t.Errorf("EnvMetadata = %+v, want source %q and a load time", before.EnvMetadata, mock.Name())
+	}
+	meta, ok := before.ProjectMetadata["acme"]
+	if !ok || meta.Source != mock.Name() || meta.Config != "acme" || meta.LoadedAt.IsZero() {
+		t.Errorf("ProjectMetadata[acme] = %+v, want mock source, config acme and a load time", meta)
+	}
+	if before.Projects["acme"].Name != "Acme" {
+		t.Errorf("snapshot project Name = %q, want %q", before.Projects["acme"].Name, "Acme")
+	}
+
+	if _, err := loader.ReloadProjects(ctx); err != nil {
+		t.Fatalf("ReloadProjects failed: %v", err)
+	}
+	after := loader.Snapshot()
+	if after.Generation <= before.Generation {
+		t.Errorf("Generation = %d after reload, want > %d", after.Generation, before.Generation)
+	}
+
+	// Snapshots are copies: mutating one does not affect the loader.
+	delete(after.Projects, "acme")
+	if _, ok := loader.Project("acme"); !ok {
+		t.Error("deleting from a snapshot should not remove the project")
+	}
+}
+
+// gatedProvider blocks FetchProject for one config until release is closed.
+type gatedProvider struct {
+	*MockProvider
+	config  string
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *gatedProvider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	if config == p.config {
+		close(p.started)
+		<-p.release
+	}
+	return p.MockProvider.FetchProject(ctx, project, config)
+}
+
+// stallingProvider never answers FetchProject for one config, returning
+// only once ctx is done.
+type stallingProvider struct {
+	*MockProvider
+	config string
+	served atomic.Int32
+}
+
+func (p *stallingProvider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	if config == p.config {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	p.served.Add(1)
+	return p.MockProvider.FetchProject(ctx, project, config)
+}
+
+func TestMultiTenantLoader_WithTenantFetchTimeout(t *testing.T) {
+	mock := NewMockProvider(nil)
+	mock.SetProjectValues("", "a", map[string]string{"PROJECT_NAME": "A"})
+	mock.SetProjectValues("", "b", map[string]string{"PROJECT_NAME": "B"})
+	provider := &stallingProvider{MockProvider: mock, config: "slow"}
+	loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](provider, nil,
+		WithTenantFetchTimeout[MTEnvConfig, MTProjectConfig](20*time.Millisecond),
+	)
+
+	_, err := loader.LoadAllProjects(context.Background(), []string{"a", "slow", "b"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("LoadAllProjects error = %v, want context.DeadlineExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "project slow") || strings.Contains(err.Error(), "project a") {
+		t.Errorf("LoadAllProjects error = %v, want only the slow tenant to fail", err)
+	}
+	if n := provider.served.Load(); n != 2 {
+		t.Errorf("served %d fast tenants, want 2", n)
+	}
+
+	cfg, err := loader.LoadProject(context.Background(), "a")
+	if err != nil || cfg.Name != "A" {
+		t.Errorf("LoadProject(a) = %+v, %v; want A", cfg, err)
+	}
+}
+
+func TestMultiTenantLoader_ReconcileKeepsConcurrentAdds(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockProvider(nil)
+	for _, code := range []string{"acme", "globex", "initech", "umbrella"} {
+		mock.SetProjectValues("", code, map[string]string{"PROJECT_NAME": code})
+	}
+
+	loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](mock, nil)
+	if _, err := loader.LoadAllProjects(ctx, []string{"acme", "globex"}); err != nil {
+		t.Fatalf("LoadAllProjects failed: %v", err)
+	}
+
+	// Hold the reconcile's fetch of globex so a LoadProject can run meanwhile.
+	gated := &gatedProvider{MockProvider: mock, config: "globex", started: make(chan struct{}), release: make(chan struct{})}
+	loader.(*multiTenantLoader[MTEnvConfig, MTProjectConfig]).provider = gated
+
+	type result struct {
+		diff *ReloadDiff
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		diff, err := loader.ReconcileProjects(ctx, []string{"globex", "umbrella"})
+		done <- result{diff, err}
+	}()
+
+	<-gated.started
+	if _, err := loader.LoadProject(ctx, "initech"); err != nil {
+		t.Fatalf("LoadProject failed: %v", err)
+	}
+	close(gated.release)
+
+	r := <-done
+	if r.err != nil {
+		t.Fatalf("ReconcileProjects failed: %v", r.err)
+	}
+	if fmt.Sprint(r.diff.Added) != "[umbrella]" || fmt.Sprint(r.diff.Removed) != "[acme]" || fmt.Sprint(r.diff.Unchanged) != "[globex]" {
+		t.Errorf("diff = %+v, want added [umbrella], removed [acme], unchanged [globex]", r.diff)
+	}
+	if got := fmt.Sprint(loader.ProjectCodes()); got != "[globex initech umbrella]" {
+		t.Errorf("ProjectCodes() = %s, want [globex initech umbrella] with the concurrent add kept", got)
+	}
+}
+
+func TestMultiTenantLoader_ReconcileClearsRemovedTenantErrors(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockProvider(nil)
+	for _, code := range []string{"acme", "globex"} {
+		mock.SetProjectValues("", code, map[string]string{"PROJECT_NAME": code})
+	}
+	provider := &partialFailureProvider{MockProvider: mock}
+	loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](provider, nil)
+	if _, err := loader.LoadAllProjects(ctx, []string{"acme", "globex"}); err != nil {
+		t.Fatalf("LoadAllProjects failed: %v", err)
+	}
+
+	// globex fails to reload and is dropped, but its error is remembered.
+	provider.fail = map[string]bool{"globex": true}
+	loader.ReloadProjects(ctx)
+	if _, ok := loader.Project("globex"); ok {
+		t.Fatal("globex should be dropped after a failed reload")
+	}
+	if _, ok := loader.ProjectErrors()["globex"]; !ok {
+		t.Fatal("globex's reload error should be recorded")
+	}
+
+	diff, err := loader.ReconcileProjects(ctx, []string{"acme"})
+	if err != nil {
+		t.Fatalf("ReconcileProjects failed: %v", err)
+	}
+	if len(diff.Removed) != 0 {
+		t.Errorf("diff.Removed = %v, want none: globex was not loaded", diff.Removed)
+	}
+	if errs := loader.ProjectErrors(); len(errs) != 0 {
+		t.Errorf("ProjectErrors() = %v, want globex's error cleared", errs)
+	}
+}
+
+// recordingProvider records the fake-clock time of each project fetch.
+type recordingProvider struct {
+	*MockProvider
+	now func() time.Duration
+
+	mu      sync.Mutex
+	fetches map[string][]time.Duration
+}
+
+func (p *recordingProvider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	if config != "" {
+		p.mu.Lock()
+		p.fetches[config] = append(p.fetches[config], p.now())
+		p.mu.Unlock()
+	}
+	return p.MockProvider.FetchProject(ctx, project, config)
+}
+
+// steppingClock is a Clock whose tickers fire on the channel returned by
+// calling it with the ticker's period.
+type steppingClock func(d time.Duration) <-chan time.Time
+
+func (c steppingClock) Now() time.Time { return time.Time{} }
+
+func (c steppingClock) NewTicker(d time.Duration) Ticker { return steppingTicker(c(d)) }
+
+type steppingTicker <-chan time.Time
+
+func (t steppingTicker) C() <-chan time.Time { return t }
+
+func (t steppingTicker) Stop() {}
+
+func TestMultiTenantWatcher_StaggeredReloads(t *testing.T) {
+	ctx := context.Background()
+	codes := []string{"acme", "globex", "initech", "umbrella"}
+	mock := NewMockProvider(nil)
+	for _, code := range codes {
+		mock.SetProjectValues("", code, map[string]string{"PROJECT_NAME": code})
+	}
+
+	loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](mock, nil)
+	if _, err := loader.LoadAllProjects(ctx, codes); err != nil {
+		t.Fatalf("LoadAllProjects failed: %v", err)
+	}
+
+	// Fake clock: waiting advances it instantly, until the first round ends.
+	const interval = time.Minute
+	var (
+		clockMu   sync.Mutex
+		now       time.Duration
+		roundDone = make(chan struct{})
+	)
+	readClock := func() time.Duration {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return now
+	}
+	rec := &recordingProvider{MockProvider: mock, now: readClock, fetches: make(map[string][]time.Duration)}
+	loader.(*multiTenantLoader[MTEnvConfig, MTProjectConfig]).provider = rec
+
+	clock := steppingClock(func(d time.Duration) <-chan time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		now += d
+		if now >= interval {
+			close(roundDone)
+			return nil // block until Stop
+		}
+		ch := make(chan time.Time, 1)
+		ch <- time.Time{}
+		return ch
+	})
+	w := NewMultiTenantWatcher(loader, interval).WithReloadStrategy(ReloadStaggered).WithClock(clock)
+	w.jitter = func(d time.Duration) time.Duration { return d / 2 }
+
+	if err := w.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	<-roundDone
+	w.Stop()
+
+	// One fetch per tenant, each in the middle of its 15s slot.
+	want := map[string]time.Duration{
+		"acme":     7500 * time.Millisecond,
+		"globex":   22500 * time.Millisecond,
+		"initech":  37500 * time.Millisecond,
+		"umbrella": 52500 * time.Millisecond,
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	for code, at := range want {
+		if got := rec.fetches[code]; len(got) != 1 || got[0] != at {
+			t.Errorf("fetches[%s] = %v, want one fetch at %v", code, got, at)
+		}
+	}
+}
+
+func TestWatchProject(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockProvider(nil)
+	mock.SetProjectValues("", "acme", map[string]string{"PROJECT_NAME": "Acme"})
+	mock.SetProjectValues("", "globex", map[string]string{"PROJECT_NAME": "Globex"})
+
+	loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](mock, nil)
+	if _, err := loader.LoadAllProjects(ctx, []string{"acme", "globex"}); err != nil {
+		t.Fatalf("LoadAllProjects failed: %v", err)
+	}
+
+	changes := make(chan [2]string, 10)
+	stop := WatchProject(ctx, loader, "acme", 10*time.Millisecond, func(old, new *MTProjectConfig) {
+		changes <- [2]string{old.Name, new.Name}
+	})
+	defer stop()
+
+	// Polls with no change, and changes to other tenants, stay quiet.
+	mock.SetProjectValues("", "globex", map[string]string{"PROJECT_NAME": "Globex Corp"})
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case c := <-changes:
+		t.Fatalf("callback fired without an acme change: %v", c)
+	default:
+	}
+	if cfg, _ := loader.Project("globex"); cfg.Name != "Globex" {
+		t.Errorf("globex Name = %q, want it left alone by the acme watch", cfg.Name)
+	}
+
+	mock.SetProjectValues("", "acme", map[string]string{"PROJECT_NAME": "Acme Inc"})
+	select {
+	case c := <-changes:
+		if c != [2]string{"Acme", "Acme Inc"} {
+			t.Errorf("change = %v, want [Acme Acme Inc]", c)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("callback did not fire after the acme config changed")
+	}
+}
+
+type mtValidatedProject struct {
+	Workers int `doppler:"WORKERS" validate:"min=1"`
+}
+
+func TestProjectLoader_LoadAndValidateRejectsBeforeStoring(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockProvider(nil)
+	mock.SetProjectValues("", "acme", map[string]string{"WORKERS": "4"})
+	mt := NewMultiTenantLoaderWithProvider[MTEnvConfig, mtValidatedProject](mock, nil)
+	pl := &projectLoader[MTEnvConfig, mtValidatedProject]{loader: mt, code: "acme"}
+
+	if _, err := pl.LoadAndValidate(ctx); err != nil {
+		t.Fatalf("LoadAndValidate failed: %v", err)
+	}
+	var changes atomic.Int32
+	pl.OnChange(func(old, new *mtValidatedProject) { changes.Add(1) })
+
+	mock.SetProjectValues("", "acme", map[string]string{"WORKERS": "-1"})
+	_, err := pl.LoadAndValidate(ctx)
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("LoadAndValidate error = %v, want ValidationErrors", err)
+	}
+	if err := pl.Warmup(ctx); err == nil {
+		t.Error("Warmup should fail for an invalid config")
+	}
+	if cfg, _ := mt.Project("acme"); cfg.Workers != 4 {
+		t.Errorf("stored Workers = %d, want the previous 4 kept", cfg.Workers)
+	}
+	if changes.Load() != 0 {
+		t.Error("OnChange should not fire for a rejected config")
+	}
+}
+
+func TestWatchProject_ValidateReload(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockProvider(nil)
+	mock.SetProjectValues("", "acme", map[string]string{"PROJECT_NAME": "Acme"})
+	loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](mock, nil)
+	if _, err := loader.LoadProject(ctx, "acme"); err != nil {
+		t.Fatalf("LoadProject failed: %v", err)
+	}
+
+	clock := NewFakeClock(time.Now())
+	checked := make(chan string, 10)
+	changes := make(chan [2]string, 10)
+	stop := WatchProject(ctx, loader, "acme", time.Minute, func(old, new *MTProjectConfig) {
+		changes <- [2]string{old.Name, new.Name}
+	},
+		WithClock[MTProjectConfig](clock),
+		WithValidateReload(func(cfg *MTProjectConfig) error {
+			checked <- cfg.Name
+			if cfg.Name == "bad" {
+				return fmt.Errorf("name must not be bad")
+			}
+			return nil
+		}),
+	)
+	defer stop()
+	clock.BlockUntil(1)
+
+	mock.SetProjectValues("", "acme", map[string]string{"PROJECT_NAME": "bad"})
+	clock.Advance(time.Minute)
+	if name := <-checked; name != "bad" {
+		t.Fatalf("validated %q, want bad", name)
+	}
+	if cfg, _ := loader.Project("acme"); cfg.Name != "Acme" {
+		t.Errorf("stored Name = %q after a rejected reload, want Acme", cfg.Name)
+	}
+
+	mock.SetProjectValues("", "acme", map[string]string{"PROJECT_NAME": "Acme Inc"})
+	clock.Advance(time.Minute)
+	select {
+	case c := <-changes:
+		if c != [2]string{"Acme", "Acme Inc"} {
+			t.Errorf("change = %v, want [Acme Acme Inc]: the rejected config was applied", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback did not fire after a valid reload")
+	}
+}
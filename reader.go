@@ -0,0 +1,95 @@
+package dopplerconfig
+
+import (
+	"context"
+	"io"
+	"io/fs"
+)
+
+// ReaderProvider reads configuration from JSON returned by an io.Reader,
+// with the same flattening and dangerous-key checks as FileProvider. The
+// reader is obtained from open on every fetch, so reloads re-read it; if it
+// is also an io.Closer it is closed after reading.
+//
+// It suits configs held in memory or embedded in the binary:
+//
+//	//go:embed defaults.json
+//	var defaults []byte
+//
+//	fallback := dopplerconfig.NewReaderProvider(func() (io.Reader, error) {
+//	    return bytes.NewReader(defaults), nil
+//	})
+type ReaderProvider struct {
+	open func() (io.Reader, error)
+	name string
+}
+
+// NewReaderProvider creates a provider that parses the JSON read from the
+// reader returned by open.
+func NewReaderProvider(open func() (io.Reader, error)) *ReaderProvider {
+	return &ReaderProvider{
+		open: open,
+		name: "reader",
+	}
+}
+
+// NewEmbedProvider creates a provider for the JSON file at path in fsys,
+// typically an embed.FS holding defaults compiled into the binary, so a
+// fallback exists even with no files on disk:
+//
+//	//go:embed defaults.json
+//	var defaultsFS embed.FS
+//
+//	fallback := dopplerconfig.NewEmbedProvider(defaultsFS, "defaults.json")
+//
+// Its name is "embed:<path>".
+func NewEmbedProvider(fsys fs.FS, path string) *ReaderProvider {
+	return NewReaderProvider(func() (io.Reader, error) {
+		return fsys.Open(path)
+	}).WithName("embed:" + path)
+}
+
+// WithName sets the name reported by Name in place of "reader". It returns
+// p for chaining.
+func (p *ReaderProvider) WithName(name string) *ReaderProvider {
+	p.name = name
+	return p
+}
+
+// Fetch reads and parses the JSON document.
+func (p *ReaderProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	return p.FetchProject(ctx, "", "")
+}
+
+// FetchProject reads and parses the JSON document. The project/config
+// parameters are ignored.
+func (p *ReaderProvider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	r, err := p.open()
+	if err != nil {
+		return nil, p.unavailable("failed to open config reader", err)
+	}
+	if c, ok := r.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, p.unavailable("failed to read config reader", err)
+	}
+
+	return DecodeJSONValues(data, p.name, "config reader")
+}
+
+func (p *ReaderProvider) unavailable(message string, err error) *ProviderError {
+	return &ProviderError{Provider: p.name, Kind: ProviderErrorUnavailable, Message: message, Err: err}
+}
+
+// Name returns the provider name.
+func (p *ReaderProvider) Name() string {
+	return p.name
+}
+
+// Close is a no-op for reader providers.
+func (p *ReaderProvider) Close() error {
+	return nil
+}
@@ -0,0 +1,107 @@
+package dopplerconfig
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ai8future/chassis-go/v10/secval"
+)
+
+//go:embed testdata/embedded_defaults.json
+var embeddedDefaults embed.FS
+
+func TestReaderProvider_StringsReader(t *testing.T) {
+	doc := `{"SERVER_PORT": 8081, "DATABASE_URL": "postgres://reader/db"}`
+	opens := 0
+	p := NewReaderProvider(func() (io.Reader, error) {
+		opens++
+		return strings.NewReader(doc), nil
+	})
+
+	loader := NewLoaderWithProvider[TestConfig](p, nil)
+	cfg, err := loader.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Server.Port != 8081 || cfg.Database.URL != "postgres://reader/db" {
+		t.Errorf("config = %+v, want values from the reader", cfg)
+	}
+
+	doc = `{"SERVER_PORT": 8082, "DATABASE_URL": "postgres://reader/db"}`
+	if cfg, _ = loader.Reload(context.Background()); cfg.Server.Port != 8082 {
+		t.Errorf("Port = %d after reload, want 8082", cfg.Server.Port)
+	}
+	if opens != 2 {
+		t.Errorf("open called %d times, want once per fetch", opens)
+	}
+	if loader.Metadata().Source != "reader" {
+		t.Errorf("Source = %q, want %q", loader.Metadata().Source, "reader")
+	}
+}
+
+func TestReaderProvider_EmbeddedFS(t *testing.T) {
+	p := NewReaderProvider(func() (io.Reader, error) {
+		return embeddedDefaults.Open("testdata/embedded_defaults.json")
+	}).WithName("embedded-defaults")
+
+	values, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if values["SERVER_PORT"] != "9000" || values["SERVER_HOST"] != "embedded.local" {
+		t.Errorf("values = %v, want flattened embedded JSON", values)
+	}
+	if p.Name() != "embedded-defaults" {
+		t.Errorf("Name = %q, want %q", p.Name(), "embedded-defaults")
+	}
+}
+
+func TestReaderProvider_Errors(t *testing.T) {
+	p := NewReaderProvider(func() (io.Reader, error) {
+		return strings.NewReader(`{"__proto__": {"polluted": "yes"}}`), nil
+	})
+	if _, err := p.Fetch(context.Background()); !errors.Is(err, secval.ErrDangerousKey) {
+		t.Errorf("Fetch error = %v, want ErrDangerousKey", err)
+	}
+
+	p = NewReaderProvider(func() (io.Reader, error) {
+		return nil, errors.New("no config")
+	})
+	_, err := p.Fetch(context.Background())
+	if pe, ok := IsProviderError(err); !ok || pe.Kind != ProviderErrorUnavailable {
+		t.Errorf("Fetch error = %v, want an unavailable ProviderError", err)
+	}
+}
+
+func TestEmbedProvider(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/defaults.json": {Data: []byte(`{"SERVER": {"PORT": 7000}, "DATABASE_URL": "postgres://embed/db"}`)},
+		"config/evil.json":     {Data: []byte(`{"constructor": "x"}`)},
+	}
+
+	p := NewEmbedProvider(fsys, "config/defaults.json")
+	loader := NewLoaderWithProvider[TestConfig](nil, p)
+	cfg, err := loader.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Server.Port != 7000 || cfg.Database.URL != "postgres://embed/db" {
+		t.Errorf("config = %+v, want embedded values", cfg)
+	}
+	if got := loader.Metadata().Source; got != "embed:config/defaults.json" {
+		t.Errorf("Source = %q, want %q", got, "embed:config/defaults.json")
+	}
+
+	if _, err := NewEmbedProvider(fsys, "config/evil.json").Fetch(context.Background()); !errors.Is(err, secval.ErrDangerousKey) {
+		t.Errorf("Fetch error = %v, want ErrDangerousKey", err)
+	}
+	if _, err := NewEmbedProvider(fsys, "config/missing.json").Fetch(context.Background()); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Fetch error = %v, want fs.ErrNotExist", err)
+	}
+}
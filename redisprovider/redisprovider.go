@@ -0,0 +1,126 @@
+// Package redisprovider provides a dopplerconfig.Provider backed by a Redis
+// hash. It lives in its own package so only programs that import it link
+// go-redis.
+package redisprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	chassiserrors "github.com/ai8future/chassis-go/v10/errors"
+	"github.com/ai8future/dopplerconfig"
+	"github.com/redis/go-redis/v9"
+)
+
+// Provider reads configuration from the fields of a Redis hash
+// (HGETALL), one config key per field.
+//
+// FetchProject reads the hash named by the key function, which defaults to
+// "config:<project>:<config>" with empty parts omitted, so a multi-tenant
+// loader's tenant "acme" reads "config:acme".
+type Provider struct {
+	client  redis.Cmdable
+	key     string
+	keyFunc func(project, config string) string
+}
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithKeyFunc sets how FetchProject maps project and config to a hash key.
+func WithKeyFunc(fn func(project, config string) string) Option {
+	return func(p *Provider) {
+		p.keyFunc = fn
+	}
+}
+
+// New creates a provider that reads the hash at key. client is
+// usually a *redis.Client; the caller owns it and is responsible for closing it.
+func New(client redis.Cmdable, key string, opts ...Option) (*Provider, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client is required")
+	}
+	if key == "" {
+		return nil, fmt.Errorf("redis hash key is required")
+	}
+
+	p := &Provider{
+		client:  client,
+		key:     key,
+		keyFunc: defaultKey,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// defaultKey joins the non-empty parts of project and config under "config:".
+func defaultKey(project, config string) string {
+	parts := []string{"config"}
+	for _, part := range []string{project, config} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
+// Fetch reads the provider's hash.
+func (p *Provider) Fetch(ctx context.Context) (map[string]string, error) {
+	return p.fetchHash(ctx, p.key)
+}
+
+// FetchProject reads the hash named by the key function. If both project and
+// config are empty, it reads the provider's own hash.
+func (p *Provider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	if project == "" && config == "" {
+		return p.Fetch(ctx)
+	}
+	return p.fetchHash(ctx, p.keyFunc(project, config))
+}
+
+func (p *Provider) fetchHash(ctx context.Context, key string) (map[string]string, error) {
+	values, err := p.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, redisError(err, key)
+	}
+
+	// HGETALL on a missing key returns an empty hash; treat that as an
+	// error so a misnamed key triggers the fallback instead of loading
+	// struct defaults.
+	if len(values) == 0 {
+		return nil, fmt.Errorf("redis hash %s not found or empty", key)
+	}
+
+	if err := dopplerconfig.ValidateKeys(values); err != nil {
+		return nil, fmt.Errorf("redis hash %s security validation failed: %w", key, err)
+	}
+
+	return values, nil
+}
+
+// redisError converts Redis failures into chassis-go ServiceErrors. Context
+// errors are wrapped unchanged.
+func redisError(err error, key string) error {
+	msg := fmt.Sprintf("redis HGETALL %s failed", key)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if redis.IsAuthError(err) {
+		return chassiserrors.UnauthorizedError(msg).WithCause(err)
+	}
+	return chassiserrors.DependencyError(msg).WithCause(err)
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "redis:" + p.key
+}
+
+// Close is a no-op; the Redis client is owned by the caller.
+func (p *Provider) Close() error {
+	return nil
+}
@@ -0,0 +1,96 @@
+package redisprovider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	chassiserrors "github.com/ai8future/chassis-go/v10/errors"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return mr, client
+}
+
+func TestProvider_Fetch(t *testing.T) {
+	mr, client := newTestRedis(t)
+	mr.HSet("config:service", "PORT", "8080", "LOG_LEVEL", "debug")
+	mr.HSet("config:acme", "PORT", "9090")
+
+	p, err := New(client, "config:service")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	values, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if values["PORT"] != "8080" || values["LOG_LEVEL"] != "debug" {
+		t.Errorf("values = %v", values)
+	}
+
+	values, err = p.FetchProject(context.Background(), "", "acme")
+	if err != nil {
+		t.Fatalf("FetchProject failed: %v", err)
+	}
+	if len(values) != 1 || values["PORT"] != "9090" {
+		t.Errorf("project values = %v, want only PORT=9090", values)
+	}
+
+	if _, err := p.FetchProject(context.Background(), "", "missing"); err == nil {
+		t.Error("expected error for missing hash")
+	}
+}
+
+func TestProvider_KeyFunc(t *testing.T) {
+	mr, client := newTestRedis(t)
+	mr.HSet("tenants/acme/prd", "PORT", "1")
+
+	p, _ := New(client, "config:service", WithKeyFunc(func(project, config string) string {
+		return "tenants/" + project + "/" + config
+	}))
+
+	values, err := p.FetchProject(context.Background(), "acme", "prd")
+	if err != nil {
+		t.Fatalf("FetchProject failed: %v", err)
+	}
+	if values["PORT"] != "1" {
+		t.Errorf("values = %v", values)
+	}
+}
+
+func TestProvider_DangerousKey(t *testing.T) {
+	mr, client := newTestRedis(t)
+	mr.HSet("config:service", "__proto__", "x")
+
+	p, _ := New(client, "config:service")
+	if _, err := p.Fetch(context.Background()); err == nil {
+		t.Error("expected dangerous key to be rejected")
+	}
+}
+
+func TestProvider_Errors(t *testing.T) {
+	mr, client := newTestRedis(t)
+	mr.HSet("config:service", "PORT", "8080")
+	p, _ := New(client, "config:service")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.Fetch(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled Fetch = %v, want context.Canceled", err)
+	}
+
+	mr.Close()
+	_, err := p.Fetch(context.Background())
+	var se *chassiserrors.ServiceError
+	if !errors.As(err, &se) || se.HTTPCode != 503 {
+		t.Errorf("Fetch with server down = %v, want 503 ServiceError", err)
+	}
+}
@@ -0,0 +1,114 @@
+package dopplerconfig
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// SnapshotProvider serves a frozen copy of the values another provider
+// returned, for reproducible test and debug runs: Capture fetches once,
+// and every later fetch returns exactly those values no matter how the
+// upstream changes. Unlike CachingProvider it never expires or refreshes.
+// Save and Load persist the snapshot as a JSON fallback file, so a run can
+// be replayed later:
+//
+//	snap := &dopplerconfig.SnapshotProvider{}
+//	if err := snap.Capture(ctx, doppler); err != nil { ... }
+//	snap.Save("testdata/prod-snapshot.json")
+//
+//	// later
+//	replay := &dopplerconfig.SnapshotProvider{}
+//	replay.Load("testdata/prod-snapshot.json")
+//	loader := dopplerconfig.NewLoaderWithProvider[AppConfig](replay, nil)
+//
+// The zero value is an empty snapshot; fetching from it fails until Capture
+// or Load succeeds.
+type SnapshotProvider struct {
+	mu     sync.RWMutex
+	values map[string]string
+	source string
+}
+
+// Capture fetches from inner once and freezes the result, replacing any
+// earlier snapshot. On error the current snapshot is kept.
+func (p *SnapshotProvider) Capture(ctx context.Context, inner Provider) error {
+	values, err := inner.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	p.set(maps.Clone(values), inner.Name())
+	return nil
+}
+
+// Save writes the snapshot to path as JSON with WriteFallbackFile, so it
+// can also be used directly as a FileProvider fallback.
+func (p *SnapshotProvider) Save(path string) error {
+	values, err := p.snapshot()
+	if err != nil {
+		return err
+	}
+	return WriteFallbackFile(path, values)
+}
+
+// Load replaces the snapshot with one written by Save, read through
+// FileProvider with its usual security checks.
+func (p *SnapshotProvider) Load(path string) error {
+	values, err := NewFileProvider(path).Fetch(context.Background())
+	if err != nil {
+		return err
+	}
+	p.set(values, "file:"+path)
+	return nil
+}
+
+func (p *SnapshotProvider) set(values map[string]string, source string) {
+	if values == nil {
+		values = map[string]string{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.values = values
+	p.source = source
+}
+
+// snapshot returns a copy of the frozen values.
+func (p *SnapshotProvider) snapshot() (map[string]string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.values == nil {
+		return nil, &ProviderError{Provider: p.name(), Kind: ProviderErrorUnavailable, Message: "snapshot is empty: call Capture or Load first"}
+	}
+	return maps.Clone(p.values), nil
+}
+
+// Fetch returns a copy of the frozen values.
+func (p *SnapshotProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	return p.snapshot()
+}
+
+// FetchProject returns a copy of the frozen values. The project/config
+// parameters are ignored; a snapshot holds a single config.
+func (p *SnapshotProvider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	return p.snapshot()
+}
+
+// Name returns "snapshot:" followed by the name of the captured provider,
+// or by "file:<path>" after Load.
+func (p *SnapshotProvider) Name() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.name()
+}
+
+func (p *SnapshotProvider) name() string {
+	if p.source == "" {
+		return "snapshot"
+	}
+	return "snapshot:" + p.source
+}
+
+// Close is a no-op; the snapshot stays usable.
+func (p *SnapshotProvider) Close() error {
+	return nil
+}
@@ -0,0 +1,84 @@
+package dopplerconfig
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotProvider_FreezesValues(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMockProvider(map[string]string{"PORT": "8080", "NAME": "svc"})
+
+	snap := &SnapshotProvider{}
+	if _, err := snap.Fetch(ctx); err == nil {
+		t.Fatal("Fetch before Capture should fail")
+	}
+	if err := snap.Capture(ctx, inner); err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+
+	inner.SetValue("PORT", "9090")
+	inner.SetValue("EXTRA", "x")
+	values, err := snap.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	want := map[string]string{"PORT": "8080", "NAME": "svc"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Fetch after upstream change = %v, want the captured %v", values, want)
+	}
+
+	values["PORT"] = "mutated"
+	values, _ = snap.FetchProject(ctx, "other", "prd")
+	if values["PORT"] != "8080" {
+		t.Errorf("Fetch returned shared state: PORT = %q after caller mutation", values["PORT"])
+	}
+	if n := inner.FetchCount(); n != 1 {
+		t.Errorf("inner fetched %d times, want 1", n)
+	}
+	if got := snap.Name(); got != "snapshot:mock" {
+		t.Errorf("Name() = %q, want snapshot:mock", got)
+	}
+
+	inner.SetError(errors.New("down"))
+	if err := snap.Capture(ctx, inner); err == nil {
+		t.Error("Capture from a failing provider should fail")
+	}
+	if values, _ := snap.Fetch(ctx); !reflect.DeepEqual(values, want) {
+		t.Errorf("failed Capture replaced the snapshot: %v", values)
+	}
+}
+
+func TestSnapshotProvider_SaveLoad(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	snap := &SnapshotProvider{}
+	if err := snap.Save(path); err == nil {
+		t.Error("Save of an empty snapshot should fail")
+	}
+	if err := snap.Capture(ctx, NewMockProvider(map[string]string{"PORT": "8080", "RATIO": "0.5"})); err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+	if err := snap.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	replay := &SnapshotProvider{}
+	if err := replay.Load(path); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	values, err := replay.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if want := map[string]string{"PORT": "8080", "RATIO": "0.5"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("replayed values = %v, want %v", values, want)
+	}
+	if got := replay.Name(); got != "snapshot:file:"+path {
+		t.Errorf("Name() = %q, want snapshot:file:%s", got, path)
+	}
+}
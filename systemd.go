@@ -0,0 +1,98 @@
+package dopplerconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// credentialsDirectoryEnv is set by systemd for units using LoadCredential=
+// or SetCredential=.
+const credentialsDirectoryEnv = "CREDENTIALS_DIRECTORY"
+
+// SystemdCredsProvider reads systemd service credentials: every regular file
+// in the credentials directory is one value, keyed by its uppercased file
+// name, with surrounding whitespace trimmed from its contents. Keys pass the
+// same dangerous-key checks as FileProvider.
+//
+//	# unit file
+//	LoadCredential=db_password:/etc/app/db_password
+//
+//	fallback := dopplerconfig.NewSystemdCredsProvider("") // $CREDENTIALS_DIRECTORY
+type SystemdCredsProvider struct {
+	dir  string
+	name string
+}
+
+// NewSystemdCredsProvider creates a provider for the credentials in dir. An
+// empty dir uses $CREDENTIALS_DIRECTORY, read on each fetch.
+func NewSystemdCredsProvider(dir string) *SystemdCredsProvider {
+	return &SystemdCredsProvider{dir: dir}
+}
+
+// WithName sets the name reported by Name in place of "systemd-creds". It
+// returns p for chaining.
+func (p *SystemdCredsProvider) WithName(name string) *SystemdCredsProvider {
+	p.name = name
+	return p
+}
+
+// Fetch reads every credential in the directory.
+func (p *SystemdCredsProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	return p.FetchProject(ctx, "", "")
+}
+
+// FetchProject reads the credentials in the project/config subdirectory of
+// the credentials directory, for units that group credentials per tenant;
+// empty project and config read the directory itself, like Fetch.
+func (p *SystemdCredsProvider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, p.error(ProviderErrorUnavailable, "credentials read aborted", err)
+	}
+
+	dir := p.dir
+	if dir == "" {
+		dir = os.Getenv(credentialsDirectoryEnv)
+	}
+	if dir == "" {
+		return nil, p.error(ProviderErrorUnavailable, "no credentials directory: $"+credentialsDirectoryEnv+" is not set", nil)
+	}
+	sub := filepath.Join(project, config)
+	if sub != "" && !filepath.IsLocal(sub) {
+		return nil, p.error(ProviderErrorInvalid, fmt.Sprintf("invalid credentials subdirectory %q", sub), nil)
+	}
+
+	files, err := readFileValues(filepath.Join(dir, sub), func(string) bool { return true })
+	if err != nil {
+		return nil, p.error(ProviderErrorUnavailable, "failed to read credentials directory", err)
+	}
+
+	result := make(map[string]string, len(files))
+	for name, value := range files {
+		result[strings.ToUpper(name)] = value
+	}
+
+	if err := ValidateKeys(result); err != nil {
+		return nil, p.error(ProviderErrorInvalid, "credential names failed security validation", err)
+	}
+	return result, nil
+}
+
+func (p *SystemdCredsProvider) error(kind ProviderErrorKind, message string, err error) *ProviderError {
+	return &ProviderError{Provider: p.Name(), Kind: kind, Message: message, Err: err}
+}
+
+// Name returns the provider name.
+func (p *SystemdCredsProvider) Name() string {
+	if p.name != "" {
+		return p.name
+	}
+	return "systemd-creds"
+}
+
+// Close is a no-op for systemd credential providers.
+func (p *SystemdCredsProvider) Close() error {
+	return nil
+}
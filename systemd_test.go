@@ -0,0 +1,70 @@
+package dopplerconfig
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeCredential(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSystemdCredsProvider(t *testing.T) {
+	dir := t.TempDir()
+	writeCredential(t, dir, "db_password", "s3cret\n")
+	writeCredential(t, dir, "API_KEY", "  key-123  ")
+	writeCredential(t, filepath.Join(dir, "billing"), "db_password", "billing-secret\n")
+	t.Setenv(credentialsDirectoryEnv, dir)
+
+	p := NewSystemdCredsProvider("")
+	values, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	want := map[string]string{"DB_PASSWORD": "s3cret", "API_KEY": "key-123"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Fetch = %v, want %v (subdirectories skipped)", values, want)
+	}
+
+	values, err = p.FetchProject(context.Background(), "billing", "")
+	if err != nil {
+		t.Fatalf("FetchProject failed: %v", err)
+	}
+	if !reflect.DeepEqual(values, map[string]string{"DB_PASSWORD": "billing-secret"}) {
+		t.Errorf("FetchProject(billing) = %v", values)
+	}
+
+	var perr *ProviderError
+	if _, err := p.FetchProject(context.Background(), "..", ""); !errors.As(err, &perr) || perr.Kind != ProviderErrorInvalid {
+		t.Errorf("FetchProject(..) error = %v, want ProviderErrorInvalid", err)
+	}
+	if p.Name() != "systemd-creds" {
+		t.Errorf("Name() = %q, want systemd-creds", p.Name())
+	}
+}
+
+func TestSystemdCredsProvider_Errors(t *testing.T) {
+	t.Setenv(credentialsDirectoryEnv, "")
+	var perr *ProviderError
+	_, err := NewSystemdCredsProvider("").Fetch(context.Background())
+	if !errors.As(err, &perr) || perr.Kind != ProviderErrorUnavailable {
+		t.Errorf("Fetch without $CREDENTIALS_DIRECTORY error = %v, want ProviderErrorUnavailable", err)
+	}
+
+	dir := t.TempDir()
+	writeCredential(t, dir, "__proto__", "x")
+	_, err = NewSystemdCredsProvider(dir).Fetch(context.Background())
+	if !errors.As(err, &perr) || perr.Kind != ProviderErrorInvalid {
+		t.Errorf("Fetch with a dangerous credential name error = %v, want ProviderErrorInvalid", err)
+	}
+}
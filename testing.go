@@ -3,17 +3,37 @@ package dopplerconfig
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"slices"
+	"strings"
 	"sync"
+	"testing"
+	"time"
 )
 
 // MockProvider is a test provider that returns configured values.
 // It implements the Provider interface for use in tests.
 type MockProvider struct {
-	mu       sync.RWMutex
-	values   map[string]string
-	projects map[string]map[string]string // project -> config values
-	fetchErr error
-	name     string
+	// StrictProjects makes FetchProject return a 404 *DopplerError for a
+	// project/config with no values set via SetProjectValues, instead of
+	// falling back to the default Fetch values. Use it to test how loaders
+	// handle missing tenants. Set it before the mock is used concurrently.
+	StrictProjects bool
+
+	mu         sync.RWMutex
+	values     map[string]string
+	projects   map[string]map[string]string // project -> config values
+	fetchErr   error
+	name       string
+	responses  []MockResponse // scripted responses, consumed in order by Fetch
+	fetchCount int
+}
+
+// MockResponse is a single scripted result for MockProvider.SetResponses.
+// If Err is non-nil it is returned; otherwise a copy of Values is returned.
+type MockResponse struct {
+	Values map[string]string
+	Err    error
 }
 
 // NewMockProvider creates a new mock provider with the given values.
@@ -35,27 +55,27 @@ func NewMockProviderWithError(err error) *MockProvider {
 	}
 }
 
-// Fetch returns the configured values.
-func (p *MockProvider) Fetch(ctx context.Context) (map[string]string, error) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-
-	if p.fetchErr != nil {
-		return nil, p.fetchErr
-	}
+// WithName sets the name reported by Name in place of "mock", which tells
+// several mocks apart in metadata and logs. It returns p for chaining.
+func (p *MockProvider) WithName(name string) *MockProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.name = name
+	return p
+}
 
-	// Return a copy to prevent mutation
-	result := make(map[string]string, len(p.values))
-	for k, v := range p.values {
-		result[k] = v
-	}
-	return result, nil
+// Fetch returns the next scripted response if any remain (see
+// SetResponses), otherwise the configured values or error.
+func (p *MockProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.fetchLocked()
 }
 
 // FetchProject returns values for a specific project/config.
 func (p *MockProvider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
 	if p.fetchErr != nil {
 		return nil, p.fetchErr
@@ -63,19 +83,54 @@ func (p *MockProvider) FetchProject(ctx context.Context, project, config string)
 
 	key := project + "/" + config
 	if values, ok := p.projects[key]; ok {
-		result := make(map[string]string, len(values))
-		for k, v := range values {
-			result[k] = v
+		return copyValues(values), nil
+	}
+
+	if p.StrictProjects {
+		return nil, &DopplerError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("mock has no values for project %q config %q", project, config),
 		}
-		return result, nil
 	}
 
 	// Fall back to default values
-	return p.Fetch(ctx)
+	return p.fetchLocked()
+}
+
+// fetchLocked implements Fetch. The caller must hold p.mu for writing.
+func (p *MockProvider) fetchLocked() (map[string]string, error) {
+	p.fetchCount++
+
+	if len(p.responses) > 0 {
+		resp := p.responses[0]
+		p.responses = p.responses[1:]
+		if resp.Err != nil {
+			return nil, resp.Err
+		}
+		return copyValues(resp.Values), nil
+	}
+
+	if p.fetchErr != nil {
+		return nil, p.fetchErr
+	}
+
+	// Return a copy to prevent mutation
+	return copyValues(p.values), nil
+}
+
+// copyValues returns a shallow copy of a values map.
+func copyValues(values map[string]string) map[string]string {
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		result[k] = v
+	}
+	return result
 }
 
 // Name returns the provider name.
 func (p *MockProvider) Name() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
 	return p.name
 }
 
@@ -118,13 +173,32 @@ func (p *MockProvider) SetError(err error) {
 	p.fetchErr = err
 }
 
-// Clear removes all values and errors.
+// SetResponses scripts the results of upcoming Fetch calls. Each call
+// consumes the next response in order; once the script is exhausted the
+// mock returns its configured values or error again. This makes
+// "fail N times, then succeed" sequences easy to express.
+func (p *MockProvider) SetResponses(responses []MockResponse) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.responses = append([]MockResponse(nil), responses...)
+}
+
+// FetchCount returns the number of Fetch calls made, including FetchProject
+// calls that fell back to the default values.
+func (p *MockProvider) FetchCount() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.fetchCount
+}
+
+// Clear removes all values, errors, and scripted responses.
 func (p *MockProvider) Clear() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	p.values = make(map[string]string)
 	p.projects = make(map[string]map[string]string)
 	p.fetchErr = nil
+	p.responses = nil
 }
 
 // TestBootstrap creates a BootstrapConfig for testing with sensible defaults.
@@ -151,6 +225,18 @@ func TestLoaderWithConfig[T any](values map[string]string) (Loader[T], *MockProv
 	return loader, mock, cfg, err
 }
 
+// TestMultiTenantLoader creates a MultiTenantLoader for testing with a
+// MockProvider serving env as the environment config and each entry of
+// projects as the config of that project code.
+func TestMultiTenantLoader[E any, P any](env map[string]string, projects map[string]map[string]string) (MultiTenantLoader[E, P], *MockProvider) {
+	mock := NewMockProvider(env)
+	for code, values := range projects {
+		mock.SetProjectValues("", code, values)
+	}
+	loader := NewMultiTenantLoaderWithProvider[E, P](mock, nil)
+	return loader, mock
+}
+
 // AssertConfigEqual is a helper to compare two configs in tests.
 // Returns an error if they are not equal.
 func AssertConfigEqual[T comparable](expected, actual T) error {
@@ -160,12 +246,65 @@ func AssertConfigEqual[T comparable](expected, actual T) error {
 	return nil
 }
 
+// AssertConfigDeepEqual fails t if expected and actual differ, comparing
+// with reflect.DeepEqual so configs with slice, map and SecretValue fields
+// work. The failure lists each differing field; SecretValue fields are
+// reported without their values.
+func AssertConfigDeepEqual[T any](t testing.TB, expected, actual *T) {
+	t.Helper()
+	if reflect.DeepEqual(expected, actual) {
+		return
+	}
+	if expected == nil || actual == nil {
+		t.Errorf("config mismatch: expected %+v, got %+v", expected, actual)
+		return
+	}
+	var diffs []string
+	diffConfig(&diffs, "", reflect.ValueOf(expected).Elem(), reflect.ValueOf(actual).Elem())
+	if len(diffs) == 0 {
+		diffs = append(diffs, "unexported fields differ")
+	}
+	t.Errorf("config mismatch:\n\t%s", strings.Join(diffs, "\n\t"))
+}
+
+// diffConfig appends a line per differing field of expected and actual,
+// descending into nested structs other than SecretValue.
+func diffConfig(diffs *[]string, path string, expected, actual reflect.Value) {
+	if expected.Kind() == reflect.Struct && expected.Type() != reflect.TypeOf(SecretValue{}) {
+		for i := 0; i < expected.NumField(); i++ {
+			field := expected.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Name
+			if path != "" {
+				name = path + "." + name
+			}
+			diffConfig(diffs, name, expected.Field(i), actual.Field(i))
+		}
+		return
+	}
+	if path == "" {
+		path = expected.Type().String()
+	}
+	if !reflect.DeepEqual(expected.Interface(), actual.Interface()) {
+		if expected.Type() == reflect.TypeOf(SecretValue{}) {
+			*diffs = append(*diffs, path+": secret values differ")
+			return
+		}
+		*diffs = append(*diffs, fmt.Sprintf("%s: expected %#v, got %#v", path, expected.Interface(), actual.Interface()))
+	}
+}
+
 // RecordingProvider wraps another provider and records all fetch calls.
 // Useful for testing that the loader calls the provider correctly.
 type RecordingProvider struct {
 	provider Provider
 	mu       sync.Mutex
 	calls    []FetchCall
+
+	closeOnce sync.Once
+	closeErr  error
 }
 
 // FetchCall records a single fetch invocation.
@@ -214,9 +353,13 @@ func (p *RecordingProvider) Name() string {
 	return "recording:" + p.provider.Name()
 }
 
-// Close delegates to the wrapped provider.
+// Close closes the wrapped provider once; later calls return the same
+// result without closing it again.
 func (p *RecordingProvider) Close() error {
-	return p.provider.Close()
+	p.closeOnce.Do(func() {
+		p.closeErr = p.provider.Close()
+	})
+	return p.closeErr
 }
 
 // Calls returns all recorded fetch calls.
@@ -235,9 +378,180 @@ func (p *RecordingProvider) CallCount() int {
 	return len(p.calls)
 }
 
+// AssertFetched fails t unless a FetchProject call for project/config was
+// recorded. Calls made through Fetch have an empty project and config.
+func (p *RecordingProvider) AssertFetched(t testing.TB, project, config string) {
+	t.Helper()
+	calls := p.Calls()
+	for _, c := range calls {
+		if c.Project == project && c.Config == config {
+			return
+		}
+	}
+	fetched := make([]string, len(calls))
+	for i, c := range calls {
+		fetched[i] = c.Project + "/" + c.Config
+	}
+	t.Errorf("expected fetch of %s/%s, got %d calls: %v", project, config, len(calls), fetched)
+}
+
+// AssertFetchCount fails t unless exactly n fetch calls were recorded.
+func (p *RecordingProvider) AssertFetchCount(t testing.TB, n int) {
+	t.Helper()
+	if got := p.CallCount(); got != n {
+		t.Errorf("expected %d fetch calls, got %d", n, got)
+	}
+}
+
 // Reset clears all recorded calls.
 func (p *RecordingProvider) Reset() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	p.calls = nil
 }
+
+// SlowProvider wraps another provider and delays every fetch by a fixed
+// duration. It honors context cancellation, returning ctx.Err() if the
+// context is done before the delay elapses. Useful for deterministic tests
+// of timeouts, watcher behavior, and cancellation.
+type SlowProvider struct {
+	provider Provider
+	delay    time.Duration
+}
+
+// NewSlowProvider wraps a provider so each fetch blocks for delay first.
+func NewSlowProvider(provider Provider, delay time.Duration) *SlowProvider {
+	return &SlowProvider{
+		provider: provider,
+		delay:    delay,
+	}
+}
+
+// Fetch waits for the delay, then delegates to the wrapped provider.
+func (p *SlowProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.provider.Fetch(ctx)
+}
+
+// FetchProject waits for the delay, then delegates to the wrapped provider.
+func (p *SlowProvider) FetchProject(ctx context.Context, project, config string) (map[string]string, error) {
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.provider.FetchProject(ctx, project, config)
+}
+
+func (p *SlowProvider) wait(ctx context.Context) error {
+	timer := time.NewTimer(p.delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Name returns the wrapped provider's name.
+func (p *SlowProvider) Name() string {
+	return "slow:" + p.provider.Name()
+}
+
+// Close delegates to the wrapped provider.
+func (p *SlowProvider) Close() error {
+	return p.provider.Close()
+}
+
+// FakeClock is a Clock for tests whose time only moves when Advance is
+// called. Pass it to WithClock or MultiTenantWatcher.WithClock to poll
+// deterministically without sleeping.
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+// NewFakeClock creates a FakeClock reading start.
+func NewFakeClock(start time.Time) *FakeClock {
+	c := &FakeClock{now: start}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTicker returns a ticker that fires every d of fake time. Like a
+// *time.Ticker it holds at most one pending tick and drops the rest.
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("dopplerconfig: non-positive interval for FakeClock.NewTicker")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTicker{clock: c, ch: make(chan time.Time, 1), period: d, next: c.now.Add(d)}
+	c.tickers = append(c.tickers, t)
+	c.cond.Broadcast()
+	return t
+}
+
+// Advance moves the fake time forward by d, firing every ticker due on the
+// way in order.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	end := c.now.Add(d)
+	for {
+		var next *fakeTicker
+		for _, t := range c.tickers {
+			if !t.next.After(end) && (next == nil || t.next.Before(next.next)) {
+				next = t
+			}
+		}
+		if next == nil {
+			break
+		}
+		c.now = next.next
+		select {
+		case next.ch <- c.now:
+		default:
+		}
+		next.next = next.next.Add(next.period)
+	}
+	c.now = end
+}
+
+// BlockUntil waits until n tickers are active, so a test can be sure a
+// watcher it started is ready before calling Advance.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.tickers) < n {
+		c.cond.Wait()
+	}
+}
+
+// fakeTicker is a Ticker driven by a FakeClock.
+type fakeTicker struct {
+	clock  *FakeClock
+	ch     chan time.Time
+	period time.Duration
+	next   time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTicker) Stop() {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tickers = slices.DeleteFunc(c.tickers, func(other *fakeTicker) bool { return other == t })
+	c.cond.Broadcast()
+}
@@ -0,0 +1,224 @@
+package dopplerconfig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMockProvider_SetResponses(t *testing.T) {
+	mock := NewMockProvider(map[string]string{"DATABASE_URL": "postgres://default/db"})
+	mock.SetResponses([]MockResponse{
+		{Err: fmt.Errorf("first failure")},
+		{Err: fmt.Errorf("second failure")},
+		{Values: map[string]string{"DATABASE_URL": "postgres://scripted/db"}},
+	})
+
+	l := NewLoaderWithProvider[TestConfig](mock, nil,
+		WithLoadRetry[TestConfig](3, time.Millisecond),
+	)
+
+	cfg, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Database.URL != "postgres://scripted/db" {
+		t.Errorf("Database.URL = %q, want scripted value", cfg.Database.URL)
+	}
+	if got := mock.FetchCount(); got != 3 {
+		t.Errorf("FetchCount() = %d, want 3", got)
+	}
+
+	// Script exhausted: the mock falls back to its configured values.
+	cfg, err = l.Reload(context.Background())
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if cfg.Database.URL != "postgres://default/db" {
+		t.Errorf("Database.URL = %q, want default value after script is exhausted", cfg.Database.URL)
+	}
+	if got := mock.FetchCount(); got != 4 {
+		t.Errorf("FetchCount() = %d, want 4", got)
+	}
+}
+
+func TestSlowProvider_Delays(t *testing.T) {
+	mock := NewMockProvider(map[string]string{"KEY": "value"})
+	slow := NewSlowProvider(mock, 20*time.Millisecond)
+
+	start := time.Now()
+	values, err := slow.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Fetch returned after %v, want at least 20ms", elapsed)
+	}
+	if values["KEY"] != "value" {
+		t.Errorf("KEY = %q, want %q", values["KEY"], "value")
+	}
+	if slow.Name() != "slow:mock" {
+		t.Errorf("Name() = %q, want %q", slow.Name(), "slow:mock")
+	}
+}
+
+func TestSlowProvider_ContextCancellation(t *testing.T) {
+	mock := NewMockProvider(map[string]string{"KEY": "value"})
+	slow := NewSlowProvider(mock, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := slow.FetchProject(ctx, "proj", "cfg")
+	if err != context.DeadlineExceeded {
+		t.Fatalf("FetchProject error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("FetchProject took %v, want cancellation to short-circuit the delay", elapsed)
+	}
+	if mock.FetchCount() != 0 {
+		t.Errorf("inner FetchCount() = %d, want 0 (never reached)", mock.FetchCount())
+	}
+}
+
+func TestTestMultiTenantLoader(t *testing.T) {
+	loader, mock := TestMultiTenantLoader[MTEnvConfig, MTProjectConfig](
+		map[string]string{"REGION": "eu-west-1"},
+		map[string]map[string]string{
+			"proj-a": {"PROJECT_NAME": "Alpha", "MAX_CONNS": "25"},
+			"proj-b": {"PROJECT_NAME": "Beta"},
+		},
+	)
+	defer loader.Close()
+	ctx := context.Background()
+
+	env, err := loader.LoadEnv(ctx)
+	if err != nil {
+		t.Fatalf("LoadEnv failed: %v", err)
+	}
+	if env.Region != "eu-west-1" {
+		t.Errorf("Region = %q, want %q", env.Region, "eu-west-1")
+	}
+
+	if _, err := loader.LoadAllProjects(ctx, []string{"proj-a", "proj-b"}); err != nil {
+		t.Fatalf("LoadAllProjects failed: %v", err)
+	}
+	a, _ := loader.Project("proj-a")
+	b, _ := loader.Project("proj-b")
+	if a == nil || a.Name != "Alpha" || a.MaxConns != 25 {
+		t.Errorf("Project(proj-a) = %+v, want Alpha with 25 conns", a)
+	}
+	if b == nil || b.Name != "Beta" || b.MaxConns != 10 {
+		t.Errorf("Project(proj-b) = %+v, want Beta with default conns", b)
+	}
+
+	mock.SetProjectValues("", "proj-b", map[string]string{"PROJECT_NAME": "Beta v2"})
+	if _, err := loader.ReloadProject(ctx, "proj-b"); err != nil {
+		t.Fatalf("ReloadProject failed: %v", err)
+	}
+	if b, _ := loader.Project("proj-b"); b == nil || b.Name != "Beta v2" {
+		t.Errorf("Project(proj-b) = %+v after reload, want Beta v2", b)
+	}
+}
+
+// fakeTB records failures instead of failing the enclosing test.
+type fakeTB struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecordingProvider_Assertions(t *testing.T) {
+	mock := NewMockProvider(map[string]string{"KEY": "value"})
+	mock.SetProjectValues("", "proj-a", map[string]string{"KEY": "a"})
+	recording := NewRecordingProvider(mock)
+	loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](recording, nil)
+	defer loader.Close()
+
+	if _, err := loader.LoadProject(context.Background(), "proj-a"); err != nil {
+		t.Fatalf("LoadProject failed: %v", err)
+	}
+
+	t.Run("pass", func(t *testing.T) {
+		recording.AssertFetched(t, "", "proj-a")
+		recording.AssertFetchCount(t, 1)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		fake := &fakeTB{TB: t}
+		recording.AssertFetched(fake, "", "proj-b")
+		recording.AssertFetchCount(fake, 2)
+
+		want := []string{
+			"expected fetch of /proj-b, got 1 calls: [/proj-a]",
+			"expected 2 fetch calls, got 1",
+		}
+		if fmt.Sprint(fake.errors) != fmt.Sprint(want) {
+			t.Errorf("failures = %q, want %q", fake.errors, want)
+		}
+	})
+}
+
+func TestAssertConfigDeepEqual(t *testing.T) {
+	type Nested struct {
+		Hosts []string
+	}
+	type config struct {
+		Name   string
+		Tags   []string
+		Limits map[string]int
+		Token  SecretValue
+		Nested Nested
+	}
+	expected := &config{
+		Name:   "api",
+		Tags:   []string{"a", "b"},
+		Limits: map[string]int{"rps": 10},
+		Token:  NewSecretValue("s3cret"),
+		Nested: Nested{Hosts: []string{"h1"}},
+	}
+
+	t.Run("equal", func(t *testing.T) {
+		actual := *expected
+		actual.Tags = []string{"a", "b"}
+		actual.Token = NewSecretValue("s3cret")
+		AssertConfigDeepEqual(t, expected, &actual)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		actual := *expected
+		actual.Tags = []string{"a", "c"}
+		actual.Token = NewSecretValue("other")
+		actual.Nested = Nested{Hosts: []string{"h2"}}
+
+		fake := &fakeTB{TB: t}
+		AssertConfigDeepEqual(fake, expected, &actual)
+		if len(fake.errors) != 1 {
+			t.Fatalf("failures = %q, want one", fake.errors)
+		}
+		msg := fake.errors[0]
+		for _, want := range []string{
+			`Tags: expected []string{"a", "b"}, got []string{"a", "c"}`,
+			"Token: secret values differ",
+			`Nested.Hosts: expected []string{"h1"}, got []string{"h2"}`,
+		} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("failure %q does not contain %q", msg, want)
+			}
+		}
+		if strings.Contains(msg, "s3cret") || strings.Contains(msg, "other") {
+			t.Errorf("failure %q leaks a secret value", msg)
+		}
+		if strings.Contains(msg, "Name") || strings.Contains(msg, "Limits") {
+			t.Errorf("failure %q reports fields that match", msg)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package dopplerconfig
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// decodeTOMLValues parses a TOML config document and flattens it exactly as
+// DecodeJSONValues would the equivalent JSON: the parsed document is
+// re-encoded as JSON and passes through the same secval, KeyPolicy and
+// flattening steps, so nested tables become underscore-joined keys and
+// arrays become comma-separated values. Dates and times become their TOML
+// text (RFC 3339 for offset date-times), and inf and nan floats the strings
+// "inf", "-inf" and "nan", since JSON cannot hold them.
+func decodeTOMLValues(data []byte, provider, what string) (map[string]string, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, &ProviderError{Provider: provider, Kind: ProviderErrorInvalid, Message: "failed to parse " + what, Err: err}
+	}
+	encoded, err := json.Marshal(jsonSafeTOML(doc))
+	if err != nil {
+		return nil, &ProviderError{Provider: provider, Kind: ProviderErrorInvalid, Message: "failed to parse " + what, Err: err}
+	}
+	return DecodeJSONValues(encoded, provider, what)
+}
+
+// jsonSafeTOML replaces the non-finite floats in a decoded TOML value, which
+// json.Marshal rejects, with their TOML spelling.
+func jsonSafeTOML(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = jsonSafeTOML(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = jsonSafeTOML(e)
+		}
+	case float64:
+		switch {
+		case math.IsInf(v, 1):
+			return "inf"
+		case math.IsInf(v, -1):
+			return "-inf"
+		case math.IsNaN(v):
+			return "nan"
+		}
+	}
+	return v
+}
@@ -0,0 +1,164 @@
+package dopplerconfig
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFileProvider_TOMLMatchesJSON(t *testing.T) {
+	dir := t.TempDir()
+	tomlPath := filepath.Join(dir, "config.toml")
+	jsonPath := filepath.Join(dir, "config.json")
+
+	toml := `# Local development config
+NAME = "svc"
+DEBUG = true
+RATIO = 0.25
+TIMEOUT = 1_000
+HOSTS = [
+  "a.example", # primary
+  "b.example",
+]
+PORTS = [8080, 8081]
+
+[server]
+host = "localhost"
+port = 8080
+
+[database.primary]
+url = 'postgres://localhost/db'
+pool = { max = 10, idle = 2 }
+
+[tls]
+cert.path = "/etc/tls/cert.pem"
+note = """
+multi \
+  line"""
+`
+	jsonDoc := `{
+  "NAME": "svc",
+  "DEBUG": true,
+  "RATIO": 0.25,
+  "TIMEOUT": 1000,
+  "HOSTS": ["a.example", "b.example"],
+  "PORTS": [8080, 8081],
+  "server": {"host": "localhost", "port": 8080},
+  "database": {"primary": {"url": "postgres://localhost/db", "pool": {"max": 10, "idle": 2}}},
+  "tls": {"cert": {"path": "/etc/tls/cert.pem"}, "note": "multi line"}
+}`
+	if err := os.WriteFile(tomlPath, []byte(toml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(jsonPath, []byte(jsonDoc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewFileProvider(tomlPath).Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("TOML Fetch failed: %v", err)
+	}
+	want, err := NewFileProvider(jsonPath).Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("JSON Fetch failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TOML values = %v\nwant JSON values %v", got, want)
+	}
+	if got["server_port"] != "8080" || got["HOSTS"] != "a.example,b.example" || got["database_primary_pool_max"] != "10" {
+		t.Errorf("unexpected flattening: %v", got)
+	}
+}
+
+func TestDecodeTOMLValues(t *testing.T) {
+	got, err := decodeTOMLValues([]byte(`
+str = "tab\there \u00e9"
+lit = 'C:\path'
+hex = 0xff
+oct = 0o17
+bin = 0b101
+neg = -42
+exp = 5e+3
+inf = -inf
+nan = nan
+date = 1979-05-27
+datetime = 1979-05-27 07:32:00Z
+local = 1979-05-27T07:32:00
+time = 07:32:00
+"quoted key" = 1
+quotes = """say "hi"""""
+nested = [[1, 2], ["a"]]
+
+[[products]]
+name = "hammer"
+`), "test", "toml document")
+	if err != nil {
+		t.Fatalf("decodeTOMLValues failed: %v", err)
+	}
+	want := map[string]string{
+		"str":        "tab\there é",
+		"lit":        `C:\path`,
+		"hex":        "255",
+		"oct":        "15",
+		"bin":        "5",
+		"neg":        "-42",
+		"exp":        "5000",
+		"inf":        "-inf",
+		"nan":        "nan",
+		"date":       "1979-05-27",
+		"datetime":   "1979-05-27T07:32:00Z",
+		"local":      "1979-05-27T07:32:00",
+		"time":       "07:32:00",
+		"quoted key": "1",
+		"quotes":     `say "hi""`,
+		"nested":     "[1 2],[a]",
+		"products":   "map[name:hammer]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeTOMLValues = %#v\nwant %#v", got, want)
+	}
+}
+
+func TestDecodeTOMLValues_Errors(t *testing.T) {
+	tests := map[string]string{
+		"duplicate key":     "a = 1\na = 2",
+		"duplicate table":   "[a]\nx = 1\n[a]\ny = 2",
+		"unterminated":      `a = "open`,
+		"missing equals":    "a 1",
+		"trailing garbage":  "a = 1 2",
+		"leading zero":      "a = 012",
+		"key is not table":  "a = 1\n[a.b]",
+		"bad escape":        `a = "\q"`,
+		"unclosed array":    "a = [1, 2",
+		"unknown bare word": "a = yes",
+	}
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeTOMLValues([]byte(src), "test", "toml document")
+			var perr *ProviderError
+			if !errors.As(err, &perr) || perr.Kind != ProviderErrorInvalid {
+				t.Errorf("decodeTOMLValues(%q) error = %v, want ProviderErrorInvalid", src, err)
+			}
+		})
+	}
+}
+
+func TestFileProvider_TOMLDangerousKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("[__proto__]\npolluted = true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewFileProvider(path).Fetch(context.Background())
+	var perr *ProviderError
+	if !errors.As(err, &perr) || perr.Kind != ProviderErrorInvalid {
+		t.Fatalf("Fetch error = %v, want a ProviderErrorInvalid", err)
+	}
+	if !strings.Contains(err.Error(), "security validation") {
+		t.Errorf("Fetch error = %v, want a security validation failure", err)
+	}
+}
@@ -1,11 +1,14 @@
 package dopplerconfig
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
 	"net"
 	"net/url"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -21,12 +24,31 @@ type ValidationError struct {
 	Field   string
 	Value   any
 	Message string
+
+	// Code identifies the failed check: a validate rule name ("min",
+	// "port", "oneof", ...), "required", "required_group", "mutex_group",
+	// or "custom" for errors returned by a Validator.
+	Code string
 }
 
 func (e *ValidationError) Error() string {
 	return fmt.Sprintf("%s: %s (value: %v)", e.Field, e.Message, e.Value)
 }
 
+// Is reports whether target is a *ValidationError whose non-empty Code and
+// Field both match e, so a partially filled ValidationError works as a
+// pattern:
+//
+//	errors.Is(err, &ValidationError{Code: "port"})
+//	errors.Is(err, &ValidationError{Field: "Server.Port", Code: "required"})
+func (e *ValidationError) Is(target error) bool {
+	t, ok := target.(*ValidationError)
+	if !ok {
+		return false
+	}
+	return (t.Code == "" || t.Code == e.Code) && (t.Field == "" || t.Field == e.Field)
+}
+
 // ValidationErrors is a collection of validation errors.
 type ValidationErrors []ValidationError
 
@@ -46,6 +68,16 @@ func (e ValidationErrors) Error() string {
 	return sb.String()
 }
 
+// Unwrap returns each ValidationError as a *ValidationError so errors.Is and
+// errors.As can inspect individual failures.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i := range e {
+		errs[i] = &e[i]
+	}
+	return errs
+}
+
 // HasErrors returns true if there are any validation errors.
 func (e ValidationErrors) HasErrors() bool {
 	return len(e) > 0
@@ -77,6 +109,7 @@ func Validate(cfg any) error {
 				errs = append(errs, ValidationError{
 					Field:   "custom",
 					Message: err.Error(),
+					Code:    "custom",
 				})
 			}
 		}
@@ -88,22 +121,42 @@ func Validate(cfg any) error {
 	return nil
 }
 
+// collectValidationErrors runs Validate on cfg and returns its errors
+// appended to the given parse errors. A non-ValidationErrors error from a
+// custom Validator is recorded with Code "custom".
+func collectValidationErrors(cfg any, parseErrs ValidationErrors) ValidationErrors {
+	errs := append(ValidationErrors{}, parseErrs...)
+	if err := Validate(cfg); err != nil {
+		if ve, ok := err.(ValidationErrors); ok {
+			errs = append(errs, ve...)
+		} else {
+			errs = append(errs, ValidationError{Field: "custom", Message: err.Error(), Code: "custom"})
+		}
+	}
+	return errs
+}
+
 func validateStruct(v reflect.Value, prefix string, errs *ValidationErrors) {
 	t := v.Type()
+	var groups, mutexGroups fieldGroups
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 		fieldValue := v.Field(i)
 
-		// Skip unexported fields
-		if !fieldValue.CanInterface() {
+		// Skip unexported fields, and fields opted out with validate:"-"
+		if !fieldValue.CanInterface() || field.Tag.Get("validate") == "-" {
 			continue
 		}
 
 		fieldName := prefix + field.Name
 
-		// Handle nested structs
+		// Handle nested structs; an optional section left entirely zero
+		// is not validated
 		if fieldValue.Kind() == reflect.Struct && !isSpecialType(fieldValue.Type()) {
+			if field.Tag.Get(TagOptional) == "true" && fieldValue.IsZero() {
+				continue
+			}
 			validateStruct(fieldValue, fieldName+".", errs)
 			continue
 		}
@@ -114,13 +167,222 @@ func validateStruct(v reflect.Value, prefix string, errs *ValidationErrors) {
 				*errs = append(*errs, ValidationError{
 					Field:   fieldName,
 					Message: "required field is missing or empty",
+					Code:    "required",
 				})
 			}
 		}
 
+		if tag := field.Tag.Get(TagRequiredGroup); tag != "" {
+			groups.add(tag, fieldName, !isZero(fieldValue))
+		}
+		if tag := field.Tag.Get(TagMutexGroup); tag != "" {
+			mutexGroups.add(tag, fieldName, !isZero(fieldValue))
+		}
+
 		// Run tag-based validations
 		validateField(field, fieldValue, fieldName, errs)
 	}
+
+	groups.checkRequired(prefix, errs)
+	mutexGroups.checkMutex(prefix, errs)
+}
+
+// fieldGroup collects the sibling fields sharing a required_group or
+// mutex_group name.
+type fieldGroup struct {
+	name    string
+	atLeast bool
+	fields  []string
+	set     []string
+}
+
+// fieldGroups holds the groups of one struct in order of first appearance.
+type fieldGroups []*fieldGroup
+
+// add records a field under the group named by tag ("name" or
+// "name,atleast").
+func (g *fieldGroups) add(tag, fieldName string, set bool) {
+	name, modifier, _ := strings.Cut(tag, ",")
+	name = strings.TrimSpace(name)
+
+	var group *fieldGroup
+	for _, existing := range *g {
+		if existing.name == name {
+			group = existing
+			break
+		}
+	}
+	if group == nil {
+		group = &fieldGroup{name: name}
+		*g = append(*g, group)
+	}
+
+	if strings.TrimSpace(modifier) == "atleast" {
+		group.atLeast = true
+	}
+	group.fields = append(group.fields, fieldName)
+	if set {
+		group.set = append(group.set, fieldName)
+	}
+}
+
+// checkRequired reports each group with no field set, or with more than one
+// set unless the group is at-least-one.
+func (g fieldGroups) checkRequired(prefix string, errs *ValidationErrors) {
+	for _, group := range g {
+		members := strings.Join(group.fields, ", ")
+		switch {
+		case len(group.set) == 0 && group.atLeast:
+			*errs = append(*errs, ValidationError{
+				Field:   prefix + group.name,
+				Message: fmt.Sprintf("required group %q: at least one of %s must be set", group.name, members),
+				Code:    "required_group",
+			})
+		case len(group.set) == 0:
+			*errs = append(*errs, ValidationError{
+				Field:   prefix + group.name,
+				Message: fmt.Sprintf("required group %q: exactly one of %s must be set", group.name, members),
+				Code:    "required_group",
+			})
+		case len(group.set) > 1 && !group.atLeast:
+			*errs = append(*errs, ValidationError{
+				Field:   prefix + group.name,
+				Value:   strings.Join(group.set, ", "),
+				Message: fmt.Sprintf("required group %q: exactly one of %s must be set, got %d", group.name, members, len(group.set)),
+				Code:    "required_group",
+			})
+		}
+	}
+}
+
+// checkMutex reports each group with more than one field set.
+func (g fieldGroups) checkMutex(prefix string, errs *ValidationErrors) {
+	for _, group := range g {
+		if len(group.set) > 1 {
+			*errs = append(*errs, ValidationError{
+				Field:   prefix + group.name,
+				Value:   strings.Join(group.set, ", "),
+				Message: fmt.Sprintf("mutex group %q: %s are mutually exclusive, set only one", group.name, strings.Join(group.set, " and ")),
+				Code:    "mutex_group",
+			})
+		}
+	}
+}
+
+// LintStruct inspects the struct tags of cfg (a struct or pointer to struct)
+// and reports contradictory tag combinations. It does not look at field
+// values. Detected conflicts:
+//   - required:"true" together with a non-empty default (the required check
+//     can never fail)
+//   - validate rules oneof and regex on the same field
+//   - validate min greater than max
+//   - a default that cannot be parsed into the field type, or that fails
+//     the field's own validate rules (e.g. default:"0" with validate:"port")
+//
+// Each issue is returned as a human-readable string prefixed by the field path.
+func LintStruct(cfg any) []string {
+	t := reflect.TypeOf(cfg)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return []string{fmt.Sprintf("expected struct, got %v", t)}
+	}
+
+	var issues []string
+	lintStruct(t, "", &issues)
+	return issues
+}
+
+func lintStruct(t reflect.Type, prefix string, issues *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("validate") == "-" {
+			continue
+		}
+
+		fieldName := prefix + field.Name
+
+		if field.Type.Kind() == reflect.Struct && !isSpecialType(field.Type) {
+			lintStruct(field.Type, fieldName+".", issues)
+			continue
+		}
+
+		if field.Tag.Get(TagRequired) == "true" && field.Tag.Get(TagDefault) != "" {
+			*issues = append(*issues, fmt.Sprintf("%s: required:\"true\" conflicts with default:%q (field can never be missing)",
+				fieldName, field.Tag.Get(TagDefault)))
+		}
+
+		rules := make(map[string]string)
+		for _, tag := range parseValidationTags(field.Tag) {
+			rules[tag.name] = tag.param
+		}
+
+		if _, hasOneOf := rules["oneof"]; hasOneOf {
+			if _, hasRegex := rules["regex"]; hasRegex {
+				*issues = append(*issues, fmt.Sprintf("%s: validate rules oneof and regex are mutually exclusive", fieldName))
+			}
+		}
+
+		minParam, hasMin := rules["min"]
+		maxParam, hasMax := rules["max"]
+		if hasMin && hasMax {
+			min, minErr := strconv.ParseInt(minParam, 10, 64)
+			max, maxErr := strconv.ParseInt(maxParam, 10, 64)
+			if minErr == nil && maxErr == nil && min > max {
+				*issues = append(*issues, fmt.Sprintf("%s: validate min=%d is greater than max=%d", fieldName, min, max))
+			}
+		}
+
+		if strings.Contains(tagKey(field), "#") && field.Type.Kind() != reflect.Slice {
+			*issues = append(*issues, fmt.Sprintf("%s: numbered key %q requires a slice field, got %s", fieldName, tagKey(field), field.Type))
+		}
+
+		if collect := field.Tag.Get(TagCollect); collect != "" {
+			if collect != "prefix" {
+				*issues = append(*issues, fmt.Sprintf("%s: unknown collect mode %q (want \"prefix\")", fieldName, collect))
+			} else if field.Type.Kind() != reflect.Map || field.Type.Key().Kind() != reflect.String {
+				*issues = append(*issues, fmt.Sprintf("%s: collect:\"prefix\" requires a map with string keys, got %s", fieldName, field.Type))
+			}
+		}
+
+		if def := field.Tag.Get(TagDefault); def != "" {
+			lintDefault(field, fieldName, def, issues)
+		}
+
+		if tag := field.Tag.Get(TagProfile); tag != "" {
+			entries, bad := profileEntries(tag)
+			for _, entry := range bad {
+				*issues = append(*issues, fmt.Sprintf("%s: profile entry %q is not name=value", fieldName, entry))
+			}
+			for _, name := range slices.Sorted(maps.Keys(entries)) {
+				lintDefault(field, fieldName+" (profile "+name+")", entries[name], issues)
+			}
+		}
+	}
+}
+
+// lintDefault parses a field's default tag into the field type and runs the
+// field's validate rules against it, so a default that would fail validation
+// is caught before it is ever used.
+func lintDefault(field reflect.StructField, fieldName, def string, issues *[]string) {
+	value := reflect.New(field.Type).Elem()
+	var err error
+	if field.Tag.Get(TagEncoding) == "json" || isJSONUnmarshaler(field.Type) {
+		err = json.Unmarshal([]byte(def), value.Addr().Interface())
+	} else {
+		err = setFieldValue(value, def)
+	}
+	if err != nil {
+		*issues = append(*issues, fmt.Sprintf("%s: default:%q cannot be parsed: %v", fieldName, def, err))
+		return
+	}
+
+	for _, tag := range parseValidationTags(field.Tag) {
+		if verr := runValidation(tag, value, fieldName); verr != nil {
+			*issues = append(*issues, fmt.Sprintf("%s: default:%q fails validate rule %s: %s", fieldName, def, tag.name, verr.Message))
+		}
+	}
 }
 
 func validateField(field reflect.StructField, value reflect.Value, name string, errs *ValidationErrors) {
@@ -174,6 +436,18 @@ func parseValidationTags(tag reflect.StructTag) []validationTag {
 }
 
 func runValidation(tag validationTag, value reflect.Value, fieldName string) *ValidationError {
+	// Rules apply to the value a set pointer field points to.
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	err := runValidationRule(tag, value, fieldName)
+	if err != nil && err.Code == "" {
+		err.Code = tag.name
+	}
+	return err
+}
+
+func runValidationRule(tag validationTag, value reflect.Value, fieldName string) *ValidationError {
 	switch tag.name {
 	case "min":
 		return validateMin(value, tag.param, fieldName)
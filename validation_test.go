@@ -1,20 +1,22 @@
 package dopplerconfig
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
 type ValidationConfig struct {
-	MinVal    int    `validate:"min=10"`
-	MaxVal    int    `validate:"max=100"`
-	Port      int    `validate:"port"`
-	URL       string `validate:"url"`
-	Email     string `validate:"email"`
-	OneOf     string `validate:"oneof=a|b|c"`
-	Regex     string `validate:"regex=^[a-z]+$"`
-	Host      string `validate:"host"`
-	Required  string `required:"true"`
-	Optional  string // No validation
+	MinVal   int    `validate:"min=10"`
+	MaxVal   int    `validate:"max=100"`
+	Port     int    `validate:"port"`
+	URL      string `validate:"url"`
+	Email    string `validate:"email"`
+	OneOf    string `validate:"oneof=a|b|c"`
+	Regex    string `validate:"regex=^[a-z]+$"`
+	Host     string `validate:"host"`
+	Required string `required:"true"`
+	Optional string // No validation
 }
 
 func TestValidate_Min(t *testing.T) {
@@ -109,6 +111,125 @@ func TestValidate_Required(t *testing.T) {
 	}
 }
 
+type requiredGroupConfig struct {
+	DatabaseURL     string `required_group:"db"`
+	DatabaseDSNFile string `required_group:"db"`
+	DatabaseSocket  string `required_group:"db"`
+
+	Cache struct {
+		RedisURL     string `required_group:"cache,atleast"`
+		MemcachedURL string `required_group:"cache"`
+	}
+}
+
+func TestValidate_RequiredGroup(t *testing.T) {
+	base := func() requiredGroupConfig {
+		var c requiredGroupConfig
+		c.Cache.RedisURL = "redis://localhost"
+		return c
+	}
+
+	// Zero set.
+	c := base()
+	err := Validate(c)
+	if !errors.Is(err, &ValidationError{Field: "db", Code: "required_group"}) {
+		t.Fatalf("Validate() = %v, want required_group error for db", err)
+	}
+	for _, want := range []string{`"db"`, "exactly one of", "DatabaseURL, DatabaseDSNFile, DatabaseSocket"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should contain %q", err, want)
+		}
+	}
+
+	// One set.
+	c.DatabaseSocket = "/var/run/postgresql"
+	if err := Validate(c); err != nil {
+		t.Errorf("Validate() with one db field set = %v, want nil", err)
+	}
+
+	// Multiple set.
+	c.DatabaseURL = "postgres://localhost/app"
+	err = Validate(c)
+	if !errors.Is(err, &ValidationError{Field: "db", Code: "required_group"}) {
+		t.Fatalf("Validate() = %v, want required_group error for two db fields", err)
+	}
+	if !strings.Contains(err.Error(), "DatabaseURL, DatabaseSocket") {
+		t.Errorf("error %q should name the fields that are set", err)
+	}
+}
+
+func TestValidate_RequiredGroupAtLeast(t *testing.T) {
+	c := requiredGroupConfig{DatabaseURL: "postgres://localhost/app"}
+
+	err := Validate(c)
+	if !errors.Is(err, &ValidationError{Field: "Cache.cache", Code: "required_group"}) {
+		t.Fatalf("Validate() = %v, want required_group error for Cache.cache", err)
+	}
+	if !strings.Contains(err.Error(), "at least one of Cache.RedisURL, Cache.MemcachedURL") {
+		t.Errorf("error %q should name the group members", err)
+	}
+
+	c.Cache.RedisURL = "redis://localhost"
+	c.Cache.MemcachedURL = "localhost:11211"
+	if err := Validate(c); err != nil {
+		t.Errorf("Validate() with both cache fields set = %v, want nil for an atleast group", err)
+	}
+}
+
+func TestValidate_MutexGroup(t *testing.T) {
+	type authConfig struct {
+		StaticToken   string `mutex_group:"auth"`
+		OAuthClientID string `mutex_group:"auth"`
+	}
+
+	if err := Validate(authConfig{}); err != nil {
+		t.Errorf("Validate() with none set = %v, want nil", err)
+	}
+	if err := Validate(authConfig{StaticToken: "tok"}); err != nil {
+		t.Errorf("Validate() with one set = %v, want nil", err)
+	}
+
+	err := Validate(authConfig{StaticToken: "tok", OAuthClientID: "client"})
+	if !errors.Is(err, &ValidationError{Field: "auth", Code: "mutex_group"}) {
+		t.Fatalf("Validate() = %v, want mutex_group error for auth", err)
+	}
+	if !strings.Contains(err.Error(), "StaticToken and OAuthClientID") {
+		t.Errorf("error %q should list the conflicting fields", err)
+	}
+}
+
+type optionalSectionConfig struct {
+	Name string `required:"true"`
+	SMTP struct {
+		Host string `required:"true"`
+		Port int    `required:"true" validate:"port"`
+	} `optional:"true"`
+}
+
+func TestValidate_OptionalSection(t *testing.T) {
+	// All zero: the section is skipped.
+	c := optionalSectionConfig{Name: "app"}
+	if err := Validate(c); err != nil {
+		t.Errorf("Validate() with no SMTP section = %v, want nil", err)
+	}
+
+	// Partial: the section is validated.
+	c.SMTP.Host = "smtp.example.com"
+	err := Validate(c)
+	if !errors.Is(err, &ValidationError{Field: "SMTP.Port", Code: "required"}) {
+		t.Errorf("Validate() with partial SMTP section = %v, want SMTP.Port required", err)
+	}
+	if errors.Is(err, &ValidationError{Field: "SMTP.Host"}) {
+		t.Errorf("Validate() = %v, SMTP.Host is set and should pass", err)
+	}
+
+	// Full: ok.
+	c.SMTP.Port = 587
+	if err := Validate(c); err != nil {
+		t.Errorf("Validate() with full SMTP section = %v, want nil", err)
+	}
+}
+
 func TestValidate_Host(t *testing.T) {
 	tests := []struct {
 		host  string
@@ -130,3 +251,171 @@ func TestValidate_Host(t *testing.T) {
 		}
 	}
 }
+
+func TestLintStruct(t *testing.T) {
+	type Nested struct {
+		Mode string `validate:"oneof=a|b,regex=^[a-z]$"`
+	}
+	type LintConfig struct {
+		URL    string `doppler:"URL" required:"true" default:"http://localhost"`
+		Port   int    `doppler:"PORT" required:"true" validate:"port"`
+		Size   int    `validate:"min=10,max=5"`
+		Nested Nested
+	}
+
+	issues := LintStruct(&LintConfig{})
+	if len(issues) != 3 {
+		t.Fatalf("LintStruct returned %d issues, want 3: %v", len(issues), issues)
+	}
+
+	wantPrefixes := []string{"URL: required", "Size: validate min", "Nested.Mode: validate rules oneof and regex"}
+	for i, want := range wantPrefixes {
+		if !strings.HasPrefix(issues[i], want) {
+			t.Errorf("issues[%d] = %q, want prefix %q", i, issues[i], want)
+		}
+	}
+}
+
+func TestLintStruct_Clean(t *testing.T) {
+	if issues := LintStruct(ValidationConfig{}); len(issues) != 0 {
+		t.Errorf("LintStruct(ValidationConfig) = %v, want no issues", issues)
+	}
+}
+
+func TestLintStruct_InvalidDefaults(t *testing.T) {
+	type DefaultsConfig struct {
+		Port    int    `doppler:"PORT" default:"0" validate:"port"`
+		Workers int    `doppler:"WORKERS" default:"abc"`
+		Level   string `doppler:"LEVEL" default:"trace" validate:"oneof=debug|info"`
+		OK      int    `doppler:"OK" default:"8080" validate:"port"`
+	}
+
+	issues := LintStruct(DefaultsConfig{})
+	if len(issues) != 3 {
+		t.Fatalf("LintStruct returned %d issues, want 3: %v", len(issues), issues)
+	}
+
+	wantPrefixes := []string{
+		`Port: default:"0" fails validate rule port`,
+		`Workers: default:"abc" cannot be parsed`,
+		`Level: default:"trace" fails validate rule oneof`,
+	}
+	for i, want := range wantPrefixes {
+		if !strings.HasPrefix(issues[i], want) {
+			t.Errorf("issues[%d] = %q, want prefix %q", i, issues[i], want)
+		}
+	}
+}
+
+func TestValidationErrors_Unwrap(t *testing.T) {
+	cfg := ValidationConfig{
+		MinVal: 5,
+		Port:   70000,
+		OneOf:  "z",
+	}
+
+	err := Validate(cfg)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+
+	// errors.As pulls out the first individual failure.
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("errors.As(%v, *ValidationError) = false, want true", err)
+	}
+
+	// errors.Is matches by code and/or field.
+	if !errors.Is(err, &ValidationError{Field: "Port", Code: "port"}) {
+		t.Error("errors.Is should match the Port port-rule failure")
+	}
+	if !errors.Is(err, &ValidationError{Code: "required"}) {
+		t.Error("errors.Is should match the missing Required field")
+	}
+	if errors.Is(err, &ValidationError{Field: "MaxVal"}) {
+		t.Error("errors.Is should not match a field that passed")
+	}
+
+	// Extract a specific field's error from the multi-error.
+	var portErr *ValidationError
+	for _, e := range err.(ValidationErrors).Unwrap() {
+		if errors.As(e, &portErr) && portErr.Field == "Port" {
+			break
+		}
+		portErr = nil
+	}
+	if portErr == nil || portErr.Code != "port" {
+		t.Errorf("Port error = %+v, want code port", portErr)
+	}
+}
+
+func TestLintStruct_Profile(t *testing.T) {
+	type ProfileConfig struct {
+		Level string `doppler:"LEVEL" profile:"dev=debug;prd=trace;stg" default:"info" validate:"oneof=debug|info|warn"`
+	}
+
+	issues := LintStruct(ProfileConfig{})
+	want := []string{
+		`Level: profile entry "stg" is not name=value`,
+		`Level (profile prd): default:"trace" fails validate rule oneof`,
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("LintStruct returned %v, want %d issues", issues, len(want))
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(issues[i], w) {
+			t.Errorf("issues[%d] = %q, want prefix %q", i, issues[i], w)
+		}
+	}
+}
+
+func TestValidate_SkipDash(t *testing.T) {
+	type ThirdParty struct {
+		Endpoint string `required:"true" validate:"url"`
+	}
+	type SkipConfig struct {
+		Port    int        `validate:"port"`
+		Legacy  int        `validate:"-" required:"true"`
+		Vendor  ThirdParty `validate:"-"`
+		Checked ThirdParty
+	}
+
+	err := Validate(SkipConfig{Port: 8080, Checked: ThirdParty{Endpoint: "https://example.com"}})
+	if err != nil {
+		t.Errorf("Validate returned %v, want fields tagged validate:\"-\" ignored", err)
+	}
+
+	err = Validate(SkipConfig{Port: 8080})
+	if !errors.Is(err, &ValidationError{Field: "Checked.Endpoint", Code: "required"}) {
+		t.Errorf("Validate error = %v, want Checked.Endpoint required", err)
+	}
+	var verrs ValidationErrors
+	if errors.As(err, &verrs) && len(verrs) != 1 {
+		t.Errorf("Validate returned %d errors, want only Checked.Endpoint: %v", len(verrs), verrs)
+	}
+}
+
+func TestLintStruct_CollectAndNumbered(t *testing.T) {
+	type CollectConfig struct {
+		Hooks  map[string]string `doppler:"HOOK_" collect:"prefix"`
+		Single string            `doppler:"ONE_" collect:"prefix"`
+		Typo   map[string]string `doppler:"TWO_" collect:"prefixes"`
+		Hosts  []string          `doppler:"HOST_#"`
+		Host   string            `doppler:"HOST_#"`
+	}
+
+	issues := LintStruct(CollectConfig{})
+	want := []string{
+		`Single: collect:"prefix" requires a map with string keys`,
+		`Typo: unknown collect mode "prefixes"`,
+		`Host: numbered key "HOST_#" requires a slice field`,
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("LintStruct returned %v, want %d issues", issues, len(want))
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(issues[i], w) {
+			t.Errorf("issues[%d] = %q, want prefix %q", i, issues[i], w)
+		}
+	}
+}
@@ -2,17 +2,50 @@ package dopplerconfig
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"maps"
+	"math/rand/v2"
+	"slices"
 	"sync"
 	"time"
 )
 
+// Clock is the source of time for watchers. The default reads the system
+// clock; tests can inject a FakeClock to drive polling without sleeping.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks like a *time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// realClock implements Clock with the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTicker(d time.Duration) Ticker { return realTicker{time.NewTicker(d)} }
+
+type realTicker struct{ t *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time { return t.t.C }
+
+func (t realTicker) Stop() { t.t.Stop() }
+
 // Watcher provides hot-reload functionality for configuration changes.
 // It periodically polls the provider and triggers callbacks when changes are detected.
 type Watcher[T any] struct {
 	loader   Loader[T]
 	interval time.Duration
 	logger   *slog.Logger
+	clock    Clock
+	jitter   float64
 
 	mu           sync.Mutex
 	running      bool
@@ -20,6 +53,19 @@ type Watcher[T any] struct {
 	doneCh       chan struct{}
 	failureCount int
 	maxFailures  int
+
+	validate      func(*T) error
+	rejectedCount int
+
+	subsets []subsetWatch
+}
+
+// subsetWatch is a group of keys polled on its own interval; see
+// WatchSubset.
+type subsetWatch struct {
+	keys     []string
+	interval time.Duration
+	fn       func(old, new map[string]string)
 }
 
 // WatcherOption configures a Watcher.
@@ -39,6 +85,29 @@ func WithWatchLogger[T any](logger *slog.Logger) WatcherOption[T] {
 	}
 }
 
+// WithClock sets the clock that drives polling. The default is the system
+// clock; pass a FakeClock in tests.
+func WithClock[T any](clock Clock) WatcherOption[T] {
+	return func(w *Watcher[T]) {
+		w.clock = clock
+	}
+}
+
+// WithJitter spreads polls by waiting a random interval within ±fraction
+// of the configured one before each poll, e.g. 0.1 for ±10%, so replicas
+// started together drift apart instead of hitting Doppler at the same
+// instant. It applies to WatchSubset intervals too and is independent of
+// failure handling. fraction must be in [0, 1); other values disable
+// jitter.
+func WithJitter[T any](fraction float64) WatcherOption[T] {
+	return func(w *Watcher[T]) {
+		if fraction < 0 || fraction >= 1 {
+			fraction = 0
+		}
+		w.jitter = fraction
+	}
+}
+
 // WithMaxFailures sets the maximum consecutive failures before stopping.
 // Set to 0 for unlimited retries (default).
 func WithMaxFailures[T any](max int) WatcherOption[T] {
@@ -47,12 +116,30 @@ func WithMaxFailures[T any](max int) WatcherOption[T] {
 	}
 }
 
+// WithValidateReload validates each polled config with validate before the
+// loader applies it. If validate returns an error the new config is
+// discarded, the previous config stays current, no OnChange callback fires,
+// and RejectedCount is incremented. Rejections do not count towards
+// WithMaxFailures.
+//
+// The check runs before the swap for loaders created by this package. Other
+// Loader implementations cannot hold a reload back, so for them the check
+// runs on the config Reload returns, after it has been applied: a failure
+// is logged and counted in RejectedCount, but the config stays current.
+// NewWatcher logs a warning when this applies.
+func WithValidateReload[T any](validate func(*T) error) WatcherOption[T] {
+	return func(w *Watcher[T]) {
+		w.validate = validate
+	}
+}
+
 // NewWatcher creates a new configuration watcher.
 func NewWatcher[T any](loader Loader[T], opts ...WatcherOption[T]) *Watcher[T] {
 	w := &Watcher[T]{
 		loader:      loader,
 		interval:    30 * time.Second,
 		logger:      slog.Default(),
+		clock:       realClock{},
 		maxFailures: 0, // Unlimited by default
 	}
 
@@ -60,6 +147,12 @@ func NewWatcher[T any](loader Loader[T], opts ...WatcherOption[T]) *Watcher[T] {
 		opt(w)
 	}
 
+	if _, ok := loader.(checkedReloader[T]); w.validate != nil && !ok {
+		w.logger.Warn("loader cannot validate a reload before applying it; WithValidateReload will only report invalid configs after the swap",
+			"loader", fmt.Sprintf("%T", loader),
+		)
+	}
+
 	return w
 }
 
@@ -101,16 +194,61 @@ func (w *Watcher[T]) IsRunning() bool {
 	return w.running
 }
 
+// RejectedCount returns how many polled configs WithValidateReload has
+// rejected since the watcher was created.
+func (w *Watcher[T]) RejectedCount() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rejectedCount
+}
+
+// WatchSubset polls keys every interval, on a ticker separate from the
+// full reload, and calls fn with the old and new values of those keys when
+// any of them changes; keys the provider lacks are absent from the maps.
+// This lets fast-changing keys such as feature flags be watched more often
+// than the rest of the config.
+//
+// The keys are fetched from the loader's primary provider, or its fallback
+// if that fails. Providers implementing KeyFetcher are asked for just these
+// keys; others are fetched in full and only these keys are compared. The
+// values are raw provider values, without loader options such as
+// WithDefaults applied, and the loader's config is not updated; call Reload
+// from fn to apply the change. Call WatchSubset before Start. It returns w
+// for chaining.
+func (w *Watcher[T]) WatchSubset(keys []string, interval time.Duration, fn func(old, new map[string]string)) *Watcher[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subsets = append(w.subsets, subsetWatch{
+		keys:     slices.Clone(keys),
+		interval: interval,
+		fn:       fn,
+	})
+	return w
+}
+
 func (w *Watcher[T]) run(ctx context.Context) {
+	var subsetsDone sync.WaitGroup
 	defer func() {
+		subsetsDone.Wait()
 		w.mu.Lock()
 		w.running = false
 		close(w.doneCh)
 		w.mu.Unlock()
 	}()
 
-	ticker := time.NewTicker(w.interval)
-	defer ticker.Stop()
+	w.mu.Lock()
+	subsets := w.subsets
+	w.mu.Unlock()
+	for _, s := range subsets {
+		subsetsDone.Add(1)
+		go func() {
+			defer subsetsDone.Done()
+			w.runSubset(ctx, s)
+		}()
+	}
+
+	ticker := w.newTicker(w.interval)
+	defer func() { ticker.Stop() }()
 
 	for {
 		select {
@@ -120,14 +258,140 @@ func (w *Watcher[T]) run(ctx context.Context) {
 		case <-w.stopCh:
 			w.logger.Info("watcher stopping: stop requested")
 			return
-		case <-ticker.C:
+		case <-ticker.C():
+			ticker = w.rearm(ticker, w.interval)
 			w.poll(ctx)
 		}
 	}
 }
 
+// newTicker returns a ticker for interval, with the first period jittered
+// when WithJitter is set.
+func (w *Watcher[T]) newTicker(interval time.Duration) Ticker {
+	if w.jitter > 0 {
+		delta := time.Duration((rand.Float64()*2 - 1) * w.jitter * float64(interval))
+		if d := interval + delta; d > 0 {
+			interval = d
+		}
+	}
+	return w.clock.NewTicker(interval)
+}
+
+// rearm replaces a ticker that just fired with one for a freshly jittered
+// period. Without jitter the ticker is kept.
+func (w *Watcher[T]) rearm(ticker Ticker, interval time.Duration) Ticker {
+	if w.jitter == 0 {
+		return ticker
+	}
+	ticker.Stop()
+	return w.newTicker(interval)
+}
+
+// runSubset polls one WatchSubset group until the watcher stops. The first
+// successful fetch sets the baseline.
+func (w *Watcher[T]) runSubset(ctx context.Context, s subsetWatch) {
+	last, err := w.fetchSubset(ctx, s.keys)
+	if err != nil {
+		w.logger.Warn("config subset fetch failed", "keys", s.keys, "error", err)
+	}
+
+	ticker := w.newTicker(s.interval)
+	defer func() { ticker.Stop() }()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C():
+			ticker = w.rearm(ticker, s.interval)
+			values, err := w.fetchSubset(ctx, s.keys)
+			if err != nil {
+				w.logger.Warn("config subset fetch failed", "keys", s.keys, "error", err)
+				continue
+			}
+			if last != nil && !maps.Equal(last, values) {
+				s.fn(last, values)
+			}
+			last = values
+		}
+	}
+}
+
+// fetchSubset fetches keys from the loader's primary provider, then its
+// fallback, using FetchKeys where supported.
+func (w *Watcher[T]) fetchSubset(ctx context.Context, keys []string) (map[string]string, error) {
+	primary, fallback := w.loader.Providers()
+	err := errors.New("loader has no providers")
+	for _, p := range []Provider{primary, fallback} {
+		if p == nil {
+			continue
+		}
+		var values map[string]string
+		if kf, ok := p.(KeyFetcher); ok {
+			values, err = kf.FetchKeys(ctx, keys)
+		} else {
+			values, err = p.Fetch(ctx)
+		}
+		if err != nil {
+			continue
+		}
+
+		subset := make(map[string]string, len(keys))
+		for _, key := range keys {
+			if value, ok := values[key]; ok {
+				subset[key] = value
+			}
+		}
+		return subset, nil
+	}
+	return nil, err
+}
+
 func (w *Watcher[T]) poll(ctx context.Context) {
-	_, err := w.loader.Reload(ctx)
+	var rejectErr error
+	var applied bool
+	var err error
+	cr, checked := w.loader.(checkedReloader[T])
+	switch {
+	case w.validate != nil && checked:
+		_, err = cr.reloadChecked(ctx, func(cfg *T) error {
+			rejectErr = w.validate(cfg)
+			return rejectErr
+		})
+	case w.validate != nil:
+		var cfg *T
+		cfg, err = w.loader.Reload(ctx)
+		if err == nil {
+			rejectErr = w.validate(cfg)
+			applied = true
+		}
+	default:
+		_, err = w.loader.Reload(ctx)
+	}
+
+	if rejectErr != nil {
+		w.mu.Lock()
+		w.failureCount = 0
+		w.rejectedCount++
+		rejected := w.rejectedCount
+		w.mu.Unlock()
+
+		if applied {
+			w.logger.Error("config reload failed validation after it was applied",
+				"error", rejectErr,
+				"rejected_count", rejected,
+			)
+		} else {
+			w.logger.Warn("config reload rejected, keeping previous config",
+				"error", rejectErr,
+				"rejected_count", rejected,
+			)
+		}
+		return
+	}
+
 	if err != nil {
 		w.mu.Lock()
 		w.failureCount++
@@ -138,6 +402,7 @@ func (w *Watcher[T]) poll(ctx context.Context) {
 		w.logger.Warn("config reload failed",
 			"error", err,
 			"consecutive_failures", failures,
+			"serving_stale", w.loader.Stale(),
 		)
 
 		if maxFail > 0 && failures >= maxFail {
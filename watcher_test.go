@@ -1,8 +1,11 @@
 package dopplerconfig
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -160,6 +163,164 @@ func TestWatcher_MaxFailures(t *testing.T) {
 	}
 }
 
+func TestWatcher_ValidateReloadRejectsBadConfig(t *testing.T) {
+	loader, mock := TestLoader[WatchTestConfig](map[string]string{"VALUE": "good"})
+	loader.Load(context.Background())
+	before := loader.Current()
+
+	var changes atomic.Int32
+	loader.OnChange(func(old, new *WatchTestConfig) { changes.Add(1) })
+
+	w := NewWatcher[WatchTestConfig](loader,
+		WithValidateReload(func(cfg *WatchTestConfig) error {
+			if cfg.Value == "bad" {
+				return fmt.Errorf("value must not be bad")
+			}
+			return nil
+		}),
+	)
+
+	mock.SetValue("VALUE", "bad")
+	w.poll(context.Background())
+
+	if loader.Current() != before {
+		t.Errorf("config swapped to %+v, want previous config kept", loader.Current())
+	}
+	if got := w.RejectedCount(); got != 1 {
+		t.Errorf("RejectedCount() = %d, want 1", got)
+	}
+	if changes.Load() != 0 {
+		t.Error("OnChange should not fire for a rejected reload")
+	}
+
+	mock.SetValue("VALUE", "fixed")
+	w.poll(context.Background())
+
+	if loader.Current().Value != "fixed" {
+		t.Errorf("config value = %q, want %q", loader.Current().Value, "fixed")
+	}
+	if got := w.RejectedCount(); got != 1 {
+		t.Errorf("RejectedCount() = %d after valid reload, want 1", got)
+	}
+}
+
+// plainLoader hides the loader's reloadChecked, standing in for a Loader
+// implemented outside this package.
+type plainLoader[T any] struct {
+	Loader[T]
+}
+
+func TestWatcher_ValidateReloadUncheckedLoader(t *testing.T) {
+	inner, mock := TestLoader[WatchTestConfig](map[string]string{"VALUE": "good"})
+	inner.Load(context.Background())
+	loader := plainLoader[WatchTestConfig]{inner}
+
+	var logs bytes.Buffer
+	w := NewWatcher[WatchTestConfig](loader,
+		WithWatchLogger[WatchTestConfig](slog.New(slog.NewTextHandler(&logs, nil))),
+		WithValidateReload(func(cfg *WatchTestConfig) error {
+			if cfg.Value == "bad" {
+				return fmt.Errorf("value must not be bad")
+			}
+			return nil
+		}),
+	)
+	if !strings.Contains(logs.String(), "loader cannot validate a reload before applying it") {
+		t.Errorf("NewWatcher did not warn about the unchecked loader; logs:\n%s", logs.String())
+	}
+
+	mock.SetValue("VALUE", "bad")
+	w.poll(context.Background())
+
+	if got := w.RejectedCount(); got != 1 {
+		t.Errorf("RejectedCount() = %d, want 1", got)
+	}
+	if !strings.Contains(logs.String(), "failed validation after it was applied") {
+		t.Errorf("rejection after the swap was not logged; logs:\n%s", logs.String())
+	}
+}
+
+// keyFetchProvider is a MockProvider that also supports FetchKeys.
+type keyFetchProvider struct {
+	*MockProvider
+	keyFetches atomic.Int32
+}
+
+func (p *keyFetchProvider) FetchKeys(ctx context.Context, keys []string) (map[string]string, error) {
+	p.keyFetches.Add(1)
+	values, err := p.MockProvider.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subset := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if v, ok := values[key]; ok {
+			subset[key] = v
+		}
+	}
+	return subset, nil
+}
+
+func TestWatcher_WatchSubset(t *testing.T) {
+	tests := []struct {
+		name    string
+		partial bool
+	}{
+		{"key fetcher", true},
+		{"full fetch", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockProvider(map[string]string{"VALUE": "v1", "FLAG_NEW_UI": "off"})
+			kf := &keyFetchProvider{MockProvider: mock}
+			var provider Provider = mock
+			if tt.partial {
+				provider = kf
+			}
+			loader := NewLoaderWithProvider[WatchTestConfig](provider, nil)
+			if _, err := loader.Load(context.Background()); err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+
+			changes := make(chan [2]map[string]string, 10)
+			w := NewWatcher(loader, WithWatchInterval[WatchTestConfig](time.Hour)).
+				WatchSubset([]string{"FLAG_NEW_UI"}, 10*time.Millisecond, func(old, new map[string]string) {
+					changes <- [2]map[string]string{old, new}
+				})
+			if err := w.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			defer w.Stop()
+
+			// A change outside the subset is not reported.
+			mock.SetValue("VALUE", "v2")
+			time.Sleep(50 * time.Millisecond)
+			select {
+			case c := <-changes:
+				t.Fatalf("callback fired for a key outside the subset: %v", c)
+			default:
+			}
+
+			mock.SetValue("FLAG_NEW_UI", "on")
+			select {
+			case c := <-changes:
+				if c[0]["FLAG_NEW_UI"] != "off" || c[1]["FLAG_NEW_UI"] != "on" || len(c[1]) != 1 {
+					t.Errorf("change = %v, want FLAG_NEW_UI off -> on only", c)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("subset callback did not fire")
+			}
+
+			if got := kf.keyFetches.Load() > 0; got != tt.partial {
+				t.Errorf("FetchKeys used = %v, want %v", got, tt.partial)
+			}
+			if cfg := loader.Current(); cfg.Value != "v1" {
+				t.Errorf("Value = %q, want the subset watch to leave the config alone", cfg.Value)
+			}
+		})
+	}
+}
+
 func TestWatch_Convenience(t *testing.T) {
 	loader, _ := TestLoader[WatchTestConfig](map[string]string{"VALUE": "x"})
 	loader.Load(context.Background())
@@ -194,3 +355,111 @@ func TestWatchWithCallback_Convenience(t *testing.T) {
 		t.Error("expected callback to fire on config change")
 	}
 }
+
+// notifyingProvider is a MockProvider that signals every Fetch.
+type notifyingProvider struct {
+	*MockProvider
+	fetched chan struct{}
+}
+
+func (p *notifyingProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	values, err := p.MockProvider.Fetch(ctx)
+	p.fetched <- struct{}{}
+	return values, err
+}
+
+func TestWatcher_WithClock(t *testing.T) {
+	mock := NewMockProvider(map[string]string{"VALUE": "v1"})
+	provider := &notifyingProvider{MockProvider: mock, fetched: make(chan struct{}, 10)}
+	loader := NewLoaderWithProvider[WatchTestConfig](provider, nil)
+	if _, err := loader.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	<-provider.fetched
+
+	clock := NewFakeClock(time.Unix(0, 0))
+	w := NewWatcher(loader,
+		WithWatchInterval[WatchTestConfig](time.Minute),
+		WithClock[WatchTestConfig](clock),
+	)
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	clock.BlockUntil(1)
+
+	for i := 1; i <= 3; i++ {
+		clock.Advance(time.Minute)
+		select {
+		case <-provider.fetched:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no poll after advancing %d intervals", i)
+		}
+	}
+	clock.Advance(30 * time.Second)
+	w.Stop()
+
+	if got := mock.FetchCount(); got != 4 {
+		t.Errorf("FetchCount() = %d, want 4 (load + 3 polls)", got)
+	}
+	if got := clock.Now(); !got.Equal(time.Unix(210, 0)) {
+		t.Errorf("Now() = %v, want 3m30s after start", got)
+	}
+}
+
+// periodClock is a FakeClock that reports the period of every new ticker.
+type periodClock struct {
+	*FakeClock
+	periods chan time.Duration
+}
+
+func (c *periodClock) NewTicker(d time.Duration) Ticker {
+	t := c.FakeClock.NewTicker(d)
+	c.periods <- d
+	return t
+}
+
+func TestWatcher_WithJitter(t *testing.T) {
+	loader, _ := TestLoader[WatchTestConfig](map[string]string{"VALUE": "x"})
+	if _, err := loader.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	const interval = time.Minute
+	clock := &periodClock{FakeClock: NewFakeClock(time.Unix(0, 0)), periods: make(chan time.Duration, 1)}
+	w := NewWatcher(loader,
+		WithWatchInterval[WatchTestConfig](interval),
+		WithClock[WatchTestConfig](clock),
+		WithJitter[WatchTestConfig](0.2),
+	)
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer w.Stop()
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 10; i++ {
+		var d time.Duration
+		select {
+		case d = <-clock.periods:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no ticker armed for poll %d", i+1)
+		}
+		if d < interval*8/10 || d > interval*12/10 {
+			t.Errorf("poll %d waited %v, want within ±20%% of %v", i+1, d, interval)
+		}
+		seen[d] = true
+		clock.Advance(d)
+	}
+	if len(seen) < 2 {
+		t.Errorf("tick spacing never varied: %v", seen)
+	}
+}
+
+func TestWithJitter_OutOfRange(t *testing.T) {
+	loader, _ := TestLoader[WatchTestConfig](nil)
+	for _, fraction := range []float64{-0.1, 1, 2} {
+		if w := NewWatcher(loader, WithJitter[WatchTestConfig](fraction)); w.jitter != 0 {
+			t.Errorf("WithJitter(%v) set jitter %v, want 0", fraction, w.jitter)
+		}
+	}
+}
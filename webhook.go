@@ -0,0 +1,173 @@
+package dopplerconfig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// WebhookSignatureHeader is the header Doppler uses to sign webhook payloads.
+const WebhookSignatureHeader = "X-Doppler-Signature"
+
+// maxWebhookBodySize caps how much of a webhook request body is read.
+const maxWebhookBodySize = 1 << 20
+
+var (
+	// ErrWebhookSignatureMissing is returned when a webhook carries no signature.
+	ErrWebhookSignatureMissing = errors.New("webhook signature missing")
+
+	// ErrWebhookSignatureMismatch is returned when a webhook signature does
+	// not match the HMAC-SHA256 of the payload.
+	ErrWebhookSignatureMismatch = errors.New("webhook signature mismatch")
+)
+
+// VerifyWebhook checks a Doppler webhook signature. It computes the
+// HMAC-SHA256 of body keyed by secret and compares it in constant time with
+// signatureHeader, which may be a bare hex digest or "sha256=<hex>".
+func VerifyWebhook(secret string, body []byte, signatureHeader string) error {
+	if secret == "" {
+		return fmt.Errorf("webhook secret is required")
+	}
+
+	sig := strings.TrimSpace(signatureHeader)
+	if sig == "" {
+		return ErrWebhookSignatureMissing
+	}
+	sig = strings.TrimPrefix(sig, "sha256=")
+
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("%w: signature is not valid hex", ErrWebhookSignatureMismatch)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrWebhookSignatureMismatch
+	}
+	return nil
+}
+
+// cacheDropper is implemented by the loaders in this package; webhook
+// handlers use it to bypass provider caches such as the Doppler ETag, since
+// the webhook means the cached values are out of date.
+type cacheDropper interface {
+	invalidateCaches()
+}
+
+// dropCaches invalidates loader's provider caches if it supports it.
+func dropCaches(loader any) {
+	if cd, ok := loader.(cacheDropper); ok {
+		cd.invalidateCaches()
+	}
+}
+
+// readVerifiedWebhook reads the request body and verifies its signature,
+// writing an error response and returning false on failure.
+func readVerifiedWebhook(w http.ResponseWriter, r *http.Request, secret string) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if err := VerifyWebhook(secret, body, r.Header.Get(WebhookSignatureHeader)); err != nil {
+		slog.Warn("rejected doppler webhook", "error", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	return body, true
+}
+
+// WebhookHandler returns an http.HandlerFunc for Doppler secret-change
+// webhooks. It verifies the request signature with VerifyWebhook and then
+// calls loader.Reload, bypassing provider caches, so changes apply immediately instead of waiting for
+// the next Watcher poll.
+//
+// Responses: 204 on a successful reload, 401 on a bad signature, 405 for
+// non-POST requests, and 500 if the reload fails.
+func WebhookHandler[T any](loader Loader[T], secret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := readVerifiedWebhook(w, r, secret); !ok {
+			return
+		}
+
+		dropCaches(loader)
+		if _, err := loader.Reload(r.Context()); err != nil {
+			slog.Error("webhook-triggered reload failed", "error", err)
+			http.Error(w, "reload failed", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// webhookPayload is the subset of a Doppler webhook body used to identify
+// which config changed.
+type webhookPayload struct {
+	Type    string `json:"type"`
+	Project struct {
+		Name string `json:"name"`
+	} `json:"project"`
+	Config struct {
+		Name    string `json:"name"`
+		Project string `json:"project"`
+	} `json:"config"`
+}
+
+// MultiTenantWebhookHandler returns an http.HandlerFunc for Doppler
+// secret-change webhooks on a multi-tenant service. After verifying the
+// signature, it reads the changed config name from the payload and, if it is
+// a loaded tenant, reloads just that tenant via ReloadProject. If the payload
+// does not identify a loaded tenant, it falls back to ReloadProjects. Either
+// way, OnProjectChange callbacks fire.
+//
+// Responses: 204 on success, 401 on a bad signature, 405 for non-POST
+// requests, and 500 if the reload fails.
+func MultiTenantWebhookHandler[E any, P any](loader MultiTenantLoader[E, P], secret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := readVerifiedWebhook(w, r, secret)
+		if !ok {
+			return
+		}
+
+		var payload webhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			slog.Warn("could not decode doppler webhook payload, reloading all projects", "error", err)
+		}
+
+		dropCaches(loader)
+
+		code := payload.Config.Name
+		if _, known := loader.Project(code); code != "" && known {
+			if _, err := loader.ReloadProject(r.Context(), code); err != nil {
+				slog.Error("webhook-triggered project reload failed", "project", code, "error", err)
+				http.Error(w, "reload failed", http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if _, err := loader.ReloadProjects(r.Context()); err != nil {
+			slog.Error("webhook-triggered reload failed", "error", err)
+			http.Error(w, "reload failed", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,177 @@
+package dopplerconfig
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signWebhook returns the "sha256=<hex>" signature for body.
+func signWebhook(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"type":"secrets.update","config":{"name":"prd"}}`)
+
+	tests := []struct {
+		name    string
+		body    []byte
+		sig     string
+		wantErr error
+	}{
+		{"valid prefixed", body, signWebhook(secret, string(body)), nil},
+		{"valid bare hex", body, strings.TrimPrefix(signWebhook(secret, string(body)), "sha256="), nil},
+		{"tampered payload", []byte(`{"type":"secrets.delete"}`), signWebhook(secret, string(body)), ErrWebhookSignatureMismatch},
+		{"wrong secret", body, signWebhook("other", string(body)), ErrWebhookSignatureMismatch},
+		{"not hex", body, "sha256=zzzz", ErrWebhookSignatureMismatch},
+		{"missing", body, "", ErrWebhookSignatureMissing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWebhook(secret, tt.body, tt.sig)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("VerifyWebhook() = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyWebhook() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	const secret = "whsec_test"
+	const body = `{"type":"secrets.update"}`
+
+	loader, mock := TestLoader[WatchTestConfig](map[string]string{"VALUE": "before"})
+	if _, err := loader.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	handler := WebhookHandler(loader, secret)
+
+	mock.SetValue("VALUE", "after")
+
+	// Tampered request is rejected without reloading.
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/doppler", strings.NewReader(body))
+	req.Header.Set(WebhookSignatureHeader, signWebhook(secret, `{"type":"other"}`))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("tampered status = %d, want 401", rec.Code)
+	}
+	if loader.Current().Value != "before" {
+		t.Errorf("Value = %q after rejected webhook, want %q", loader.Current().Value, "before")
+	}
+
+	// Valid request triggers a reload.
+	req = httptest.NewRequest(http.MethodPost, "/webhooks/doppler", strings.NewReader(body))
+	req.Header.Set(WebhookSignatureHeader, signWebhook(secret, body))
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("valid status = %d, want 204", rec.Code)
+	}
+	if loader.Current().Value != "after" {
+		t.Errorf("Value = %q after webhook, want %q", loader.Current().Value, "after")
+	}
+}
+
+func TestWebhookHandler_BypassesProviderCache(t *testing.T) {
+	const secret = "whsec_test"
+	const body = `{"type":"secrets.update"}`
+
+	mock := NewMockProvider(map[string]string{"VALUE": "before"})
+	loader := NewLoaderWithProvider[WatchTestConfig](NewCachingProvider(mock, time.Hour), nil)
+	if _, err := loader.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	mock.SetValue("VALUE", "after")
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/doppler", strings.NewReader(body))
+	req.Header.Set(WebhookSignatureHeader, signWebhook(secret, body))
+	rec := httptest.NewRecorder()
+	WebhookHandler(loader, secret)(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", rec.Code)
+	}
+	if loader.Current().Value != "after" {
+		t.Errorf("Value = %q, want %q despite the cached result", loader.Current().Value, "after")
+	}
+}
+
+func TestMultiTenantWebhookHandler(t *testing.T) {
+	const secret = "whsec_test"
+
+	mock := NewMockProvider(nil)
+	mock.SetProjectValues("", "tenant-a", map[string]string{"PROJECT_NAME": "A1"})
+	mock.SetProjectValues("", "tenant-b", map[string]string{"PROJECT_NAME": "B1"})
+	recorder := NewRecordingProvider(mock)
+
+	loader := NewMultiTenantLoaderWithProvider[MTEnvConfig, MTProjectConfig](recorder, nil)
+	if _, err := loader.LoadAllProjects(context.Background(), []string{"tenant-a", "tenant-b"}); err != nil {
+		t.Fatalf("LoadAllProjects failed: %v", err)
+	}
+
+	var diffs []*ReloadDiff
+	loader.OnProjectChange(func(diff *ReloadDiff) {
+		diffs = append(diffs, diff)
+	})
+
+	handler := MultiTenantWebhookHandler(loader, secret)
+	send := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/doppler", strings.NewReader(body))
+		req.Header.Set(WebhookSignatureHeader, signWebhook(secret, body))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+
+	mock.SetProjectValues("", "tenant-a", map[string]string{"PROJECT_NAME": "A2"})
+	mock.SetProjectValues("", "tenant-b", map[string]string{"PROJECT_NAME": "B2"})
+	recorder.Reset()
+
+	// Targeted webhook reloads only tenant-a.
+	if code := send(`{"type":"config.secrets.update","config":{"name":"tenant-a"}}`); code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", code)
+	}
+	if calls := recorder.Calls(); len(calls) != 1 || calls[0].Config != "tenant-a" {
+		t.Errorf("fetch calls = %+v, want a single fetch of tenant-a", calls)
+	}
+	if cfg, _ := loader.Project("tenant-a"); cfg.Name != "A2" {
+		t.Errorf("tenant-a Name = %q, want %q", cfg.Name, "A2")
+	}
+	if cfg, _ := loader.Project("tenant-b"); cfg.Name != "B1" {
+		t.Errorf("tenant-b Name = %q, want unchanged %q", cfg.Name, "B1")
+	}
+	if len(diffs) != 1 || len(diffs[0].Unchanged) != 1 || diffs[0].Unchanged[0] != "tenant-a" {
+		t.Errorf("diffs = %+v, want one diff naming tenant-a", diffs)
+	}
+
+	// Payload without a config reloads every tenant.
+	recorder.Reset()
+	if code := send(`{"type":"config.secrets.update"}`); code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", code)
+	}
+	if got := recorder.CallCount(); got != 2 {
+		t.Errorf("fetch calls = %d, want 2 (full reload)", got)
+	}
+	if cfg, _ := loader.Project("tenant-b"); cfg.Name != "B2" {
+		t.Errorf("tenant-b Name = %q, want %q after full reload", cfg.Name, "B2")
+	}
+	if len(diffs) != 2 {
+		t.Errorf("callback fired %d times, want 2", len(diffs))
+	}
+}